@@ -0,0 +1,111 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/types"
+)
+
+// Answer is the summary/analysis/solutions shape requested by the prompts
+type Answer struct {
+	Summary   string   `json:"response"`
+	Analysis  string   `json:"analysis"`
+	Solutions []string `json:"solutions"`
+}
+
+// ParseAnswer extracts an Answer from a provider response. Providers often
+// wrap the requested JSON object in a fenced block inside the "response"
+// field, optionally preceded by prose; both layouts are handled.
+func ParseAnswer(response string) Answer {
+	answer := answerObject(response)
+	if answer == nil {
+		return Answer{Summary: strings.TrimSpace(response)}
+	}
+
+	var out Answer
+	if s, ok := answer["response"].(string); ok {
+		out.Summary = strings.TrimSpace(s)
+	}
+	if a, ok := answer["analysis"].(string); ok {
+		out.Analysis = strings.TrimSpace(a)
+	}
+	if sols, ok := answer["solutions"].([]interface{}); ok {
+		for _, s := range sols {
+			if str, ok := s.(string); ok && str != "" {
+				out.Solutions = append(out.Solutions, str)
+			}
+		}
+	}
+	return out
+}
+
+// ParseStructured extracts and validates the structured fields requested by
+// the prompts. An error means the model answered in prose or with an
+// invalid object, and callers should fall back to ParseAnswer.
+func ParseStructured(response string) (*types.Analysis, error) {
+	answer := answerObject(response)
+	if answer == nil {
+		return nil, fmt.Errorf("response is not a JSON object")
+	}
+	if _, ok := answer["root_cause"]; !ok {
+		return nil, fmt.Errorf("response has no root_cause")
+	}
+	b, err := json.Marshal(answer)
+	if err != nil {
+		return nil, err
+	}
+	var out types.Analysis
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, fmt.Errorf("invalid structured analysis: %w", err)
+	}
+	out.RootCause = strings.TrimSpace(out.RootCause)
+	out.Category = types.Category(strings.ToLower(strings.TrimSpace(string(out.Category))))
+	if err := out.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid structured analysis: %w", err)
+	}
+	return &out, nil
+}
+
+// answerObject returns the JSON object holding the answer, preferring one
+// embedded in the "response" field. It returns nil when response is not
+// JSON.
+func answerObject(response string) map[string]interface{} {
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(response), &data); err != nil {
+		return nil
+	}
+
+	if resp, ok := data["response"].(string); ok {
+		inner := strings.TrimSpace(resp)
+		if start := strings.Index(inner, "{"); start != -1 {
+			if end := strings.LastIndex(inner, "}"); end > start {
+				var embedded map[string]interface{}
+				if json.Unmarshal([]byte(inner[start:end+1]), &embedded) == nil {
+					if _, ok := embedded["response"]; !ok {
+						preface := strings.TrimSpace(inner[:start])
+						preface = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(preface, "json"), "```"))
+						embedded["response"] = preface
+					}
+					return embedded
+				}
+			}
+		}
+	}
+	return data
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// ProviderAnthropic selects the Anthropic Claude backend
+	ProviderAnthropic = "anthropic"
+
+	// DefaultAnthropicURL is the Anthropic API endpoint
+	DefaultAnthropicURL = "https://api.anthropic.com"
+	// DefaultAnthropicModel is used when no model is configured
+	DefaultAnthropicModel = "claude-sonnet-4-5"
+	// AnthropicAPIKeyEnv is read when Config.APIKey is empty
+	AnthropicAPIKeyEnv = "ANTHROPIC_API_KEY"
+
+	anthropicVersion = "2023-06-01"
+)
+
+// DefaultMaxTokens caps completions for providers that require a limit
+const DefaultMaxTokens = 2048
+
+func init() {
+	Register(ProviderAnthropic, func(cfg Config) (LLM, error) {
+		return NewAnthropicLLM(cfg), nil
+	})
+	RegisterValidator(ProviderAnthropic, func(cfg Config) []error {
+		var errs []error
+		if apiKey(cfg, AnthropicAPIKeyEnv) == "" {
+			errs = append(errs, &ConfigError{Field: "API key", Message: "anthropic requires an API key (set " + AnthropicAPIKeyEnv + ")"})
+		}
+		// Anthropic accepts a narrower range than the 0 to 2 checked for
+		// all providers
+		if t := cfg.Temperature; t != nil && *t > 1 {
+			errs = append(errs, &ConfigError{Field: "temperature", Message: fmt.Sprintf("anthropic accepts 0 to 1, got %g", *t)})
+		}
+		return errs
+	})
+}
+
+// AnthropicLLM talks to the Anthropic Messages API
+type AnthropicLLM struct {
+	baseURL      string
+	apiKey       string
+	model        string
+	sampling     sampling
+	systemPrompt string
+	client       *http.Client
+}
+
+// NewAnthropicLLM creates an Anthropic backed LLM
+func NewAnthropicLLM(cfg Config) *AnthropicLLM {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultAnthropicURL
+	}
+	model := cfg.Model
+	if model == "" {
+		model = DefaultAnthropicModel
+	}
+	// Anthropic requires a completion limit
+	s := newSampling(cfg)
+	if s.maxTokens == 0 {
+		s.maxTokens = DefaultMaxTokens
+	}
+	return &AnthropicLLM{
+		baseURL:      baseURL,
+		apiKey:       apiKey(cfg, AnthropicAPIKeyEnv),
+		model:        model,
+		sampling:     s,
+		systemPrompt: cfg.SystemPrompt,
+		client:       newHTTPClient(cfg),
+	}
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model         string             `json:"model"`
+	MaxTokens     int                `json:"max_tokens"`
+	System        string             `json:"system,omitempty"`
+	Messages      []anthropicMessage `json:"messages"`
+	Temperature   *float64           `json:"temperature,omitempty"`
+	TopP          *float64           `json:"top_p,omitempty"`
+	StopSequences []string           `json:"stop_sequences,omitempty"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// Analyze sends the query to Anthropic and returns the answer in the
+// Lightspeed response shape
+func (a *AnthropicLLM) Analyze(ctx context.Context, query string) (string, error) {
+	req := anthropicRequest{
+		Model:         a.model,
+		MaxTokens:     a.sampling.maxTokens,
+		System:        a.systemPrompt,
+		Messages:      []anthropicMessage{{Role: "user", Content: query}},
+		Temperature:   a.sampling.temperature,
+		TopP:          a.sampling.topP,
+		StopSequences: a.sampling.stop,
+	}
+	headers := map[string]string{
+		"x-api-key":         a.apiKey,
+		"anthropic-version": anthropicVersion,
+	}
+
+	var resp anthropicResponse
+	if err := postJSON(ctx, a.client, ProviderAnthropic, joinURL(a.baseURL, "/v1/messages"), headers, req, &resp); err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	for _, c := range resp.Content {
+		if c.Type == "text" {
+			text.WriteString(c.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("anthropic returned an empty answer (stop reason %s)", resp.StopReason)
+	}
+
+	return lightspeedShape(text.String(), resp.Usage.InputTokens, resp.Usage.OutputTokens)
+}
+
+// Name implements LLM
+func (a *AnthropicLLM) Name() string {
+	return ProviderAnthropic
+}
+
+// Model implements LLM
+func (a *AnthropicLLM) Model() string {
+	return a.model
+}
@@ -0,0 +1,262 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ProviderAzureOpenAI selects an Azure OpenAI deployment
+	ProviderAzureOpenAI = "azure-openai"
+
+	// DefaultAzureAPIVersion is the data-plane api-version used by default
+	DefaultAzureAPIVersion = "2024-10-21"
+	// AzureOpenAIAPIVersionEnv holds the default of --api-version
+	AzureOpenAIAPIVersionEnv = "TKN_ASSIST_AZURE_API_VERSION"
+
+	// AzureOpenAIAPIKeyEnv is read when Config.APIKey is empty
+	AzureOpenAIAPIKeyEnv = "AZURE_OPENAI_API_KEY"
+	// AzureOpenAIEndpointEnv is read when Config.BaseURL is empty
+	AzureOpenAIEndpointEnv = "AZURE_OPENAI_ENDPOINT"
+	// AzureOpenAIADTokenEnv holds a pre-acquired Entra ID access token
+	AzureOpenAIADTokenEnv = "AZURE_OPENAI_AD_TOKEN"
+
+	// Entra ID client credentials, as used by the Azure SDKs
+	azureTenantIDEnv     = "AZURE_TENANT_ID"
+	azureClientIDEnv     = "AZURE_CLIENT_ID"
+	azureClientSecretEnv = "AZURE_CLIENT_SECRET"
+
+	azureAuthorityURL      = "https://login.microsoftonline.com"
+	azureCognitiveScope    = "https://cognitiveservices.azure.com/.default"
+	azureTokenRefreshSlack = 2 * time.Minute
+)
+
+func init() {
+	Register(ProviderAzureOpenAI, func(cfg Config) (LLM, error) {
+		return NewAzureOpenAILLM(cfg), nil
+	})
+	RegisterValidator(ProviderAzureOpenAI, func(cfg Config) []error {
+		var errs []error
+		if azureEndpoint(cfg) == "" {
+			errs = append(errs, &ConfigError{Field: "base URL", Message: "azure-openai requires the resource endpoint (set " + AzureOpenAIEndpointEnv + ")"})
+		}
+		if cfg.Model == "" {
+			errs = append(errs, &ConfigError{Field: "model", Message: "azure-openai requires the deployment name as model"})
+		}
+		if apiKey(cfg, AzureOpenAIAPIKeyEnv) == "" && !hasEntraCredentials() {
+			errs = append(errs, &ConfigError{Field: "API key", Message: "azure-openai requires " + AzureOpenAIAPIKeyEnv + ", " + AzureOpenAIADTokenEnv + " or Entra ID client credentials"})
+		}
+		return errs
+	})
+}
+
+// AzureOpenAILLM talks to an Azure OpenAI deployment. It authenticates with
+// an API key or, when none is configured, with an Entra ID bearer token.
+type AzureOpenAILLM struct {
+	endpoint     string
+	deployment   string
+	apiVersion   string
+	apiKey       string
+	tokens       *entraTokenSource
+	sampling     sampling
+	systemPrompt string
+	client       *http.Client
+}
+
+// NewAzureOpenAILLM creates an Azure OpenAI backed LLM. cfg.Model names the
+// deployment.
+func NewAzureOpenAILLM(cfg Config) *AzureOpenAILLM {
+	apiVersion := cfg.APIVersion
+	if apiVersion == "" {
+		apiVersion = DefaultAzureAPIVersion
+	}
+	llm := &AzureOpenAILLM{
+		endpoint:     azureEndpoint(cfg),
+		deployment:   cfg.Model,
+		apiVersion:   apiVersion,
+		apiKey:       apiKey(cfg, AzureOpenAIAPIKeyEnv),
+		sampling:     newSampling(cfg),
+		systemPrompt: cfg.SystemPrompt,
+		client:       newHTTPClient(cfg),
+	}
+	if llm.apiKey == "" {
+		llm.tokens = newEntraTokenSource(cfg.Timeout)
+	}
+	return llm
+}
+
+// Analyze sends the query to the deployment and returns the answer in the
+// Lightspeed response shape
+func (a *AzureOpenAILLM) Analyze(ctx context.Context, query string) (string, error) {
+	headers, err := a.headers(ctx)
+	if err != nil {
+		return "", err
+	}
+	// The deployment selects the model, so no model field is sent
+	req := newChatRequest("", a.systemPrompt, query, a.sampling)
+
+	var resp chatResponse
+	if err := postJSON(ctx, a.client, ProviderAzureOpenAI, a.completionsURL(), headers, req, &resp); err != nil {
+		return "", err
+	}
+	return resp.lightspeedShape(ProviderAzureOpenAI)
+}
+
+// AnalyzeStream implements Streamer
+func (a *AzureOpenAILLM) AnalyzeStream(ctx context.Context, query string) (<-chan StreamChunk, error) {
+	headers, err := a.headers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req := newChatRequest("", a.systemPrompt, query, a.sampling)
+	req.Stream = true
+
+	body, err := postStream(ctx, a.client, ProviderAzureOpenAI, a.completionsURL(), headers, req)
+	if err != nil {
+		return nil, err
+	}
+	return streamChatSSE(ctx, ProviderAzureOpenAI, body), nil
+}
+
+func (a *AzureOpenAILLM) completionsURL() string {
+	return joinURL(a.endpoint, "/openai/deployments/"+url.PathEscape(a.deployment)+"/chat/completions") +
+		"?api-version=" + url.QueryEscape(a.apiVersion)
+}
+
+// headers returns the authentication headers for the next request
+func (a *AzureOpenAILLM) headers(ctx context.Context) (map[string]string, error) {
+	if a.apiKey != "" {
+		return map[string]string{"api-key": a.apiKey}, nil
+	}
+	token, err := a.tokens.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"Authorization": "Bearer " + token}, nil
+}
+
+// Name implements LLM
+func (a *AzureOpenAILLM) Name() string {
+	return ProviderAzureOpenAI
+}
+
+// Model implements LLM
+func (a *AzureOpenAILLM) Model() string {
+	return a.deployment
+}
+
+func azureEndpoint(cfg Config) string {
+	if cfg.BaseURL != "" {
+		return cfg.BaseURL
+	}
+	return os.Getenv(AzureOpenAIEndpointEnv)
+}
+
+// hasEntraCredentials reports whether an Entra ID token can be obtained
+func hasEntraCredentials() bool {
+	if os.Getenv(AzureOpenAIADTokenEnv) != "" {
+		return true
+	}
+	return os.Getenv(azureTenantIDEnv) != "" && os.Getenv(azureClientIDEnv) != "" && os.Getenv(azureClientSecretEnv) != ""
+}
+
+// entraTokenSource returns a static token from AZURE_OPENAI_AD_TOKEN or runs
+// the client credentials flow and caches the result until shortly before it
+// expires
+type entraTokenSource struct {
+	static       string
+	tenantID     string
+	clientID     string
+	clientSecret string
+	client       *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// newEntraTokenSource returns a token source with a client of its own, so
+// the extra headers and TLS settings of the provider endpoint are never
+// applied to the Entra ID login
+func newEntraTokenSource(timeout time.Duration) *entraTokenSource {
+	return &entraTokenSource{
+		static:       os.Getenv(AzureOpenAIADTokenEnv),
+		tenantID:     os.Getenv(azureTenantIDEnv),
+		clientID:     os.Getenv(azureClientIDEnv),
+		clientSecret: os.Getenv(azureClientSecretEnv),
+		client:       &http.Client{Timeout: timeout},
+	}
+}
+
+// Token returns a valid access token for Azure OpenAI
+func (s *entraTokenSource) Token(ctx context.Context) (string, error) {
+	if s.static != "" {
+		return s.static, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && time.Now().Before(s.expires) {
+		return s.token, nil
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {s.clientID},
+		"client_secret": {s.clientSecret},
+		"scope":         {azureCognitiveScope},
+	}
+	tokenURL := azureAuthorityURL + "/" + url.PathEscape(s.tenantID) + "/oauth2/v2.0/token"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("entra ID token request failed: %w", err)
+	}
+	defer safeClose(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", newAPIError("entra ID", resp.StatusCode, body)
+	}
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tok); err != nil || tok.AccessToken == "" {
+		return "", fmt.Errorf("invalid entra ID token response")
+	}
+	s.token = tok.AccessToken
+	s.expires = time.Now().Add(time.Duration(tok.ExpiresIn)*time.Second - azureTokenRefreshSlack)
+	return s.token, nil
+}
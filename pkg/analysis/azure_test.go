@@ -0,0 +1,39 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewAzureOpenAILLM(t *testing.T) {
+	t.Setenv(AzureOpenAIAPIKeyEnv, "")
+	t.Setenv(AzureOpenAIADTokenEnv, "entra-token")
+	llm := NewAzureOpenAILLM(Config{
+		Provider:    ProviderAzureOpenAI,
+		BaseURL:     "https://example.openai.azure.com",
+		Model:       "gpt-4o",
+		APIVersion:  "2025-01-01-preview",
+		Headers:     map[string]string{"X-Api-Key": "secret"},
+		InsecureTLS: true,
+	})
+	if url := llm.completionsURL(); !strings.HasSuffix(url, "api-version=2025-01-01-preview") {
+		t.Errorf("api-version not applied: %s", url)
+	}
+	if llm.tokens == nil || llm.tokens.client == llm.client || llm.tokens.client.Transport != nil {
+		t.Error("expected the Entra ID login to use a default client of its own")
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores provider responses by failure signature so identical
+// failures, e.g. a flaky step failing on every retry, are analyzed once
+type Cache interface {
+	Get(key string) (string, bool)
+	Set(key, response string)
+}
+
+// volatile matches run specific tokens that do not change the failure:
+// timestamps, UUIDs and long hex identifiers such as digests or pod hashes
+var volatile = regexp.MustCompile(
+	`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?` +
+		`|\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b` +
+		`|\b[0-9a-f]{12,}\b`)
+
+// Signature returns the query with run specific tokens masked, so
+// retries of the same failure share a cache entry
+func Signature(query string) string {
+	return volatile.ReplaceAllString(query, "*")
+}
+
+// CacheKey identifies the answer of a provider and model to a query.
+// settings fingerprints the rest of the configuration that shapes the
+// answer, see CacheSettings.
+func CacheKey(provider, model, settings, query string) string {
+	sum := sha256.Sum256([]byte(provider + "\x00" + model + "\x00" + PromptVersion + "\x00" + settings + "\x00" + Signature(query)))
+	return hex.EncodeToString(sum[:])
+}
+
+// CacheSettings returns the settings of cfg that change answers: the
+// system prompt, sampling and reasoning parameters and the safety threshold
+func CacheSettings(cfg Config) string {
+	b, _ := json.Marshal(struct {
+		SystemPrompt        string
+		MaxTokens           int
+		Temperature         *float64
+		TopP                *float64
+		Stop                []string
+		ReasoningEffort     string
+		MaxCompletionTokens int
+		SafetyThreshold     string
+	}{cfg.SystemPrompt, cfg.MaxTokens, cfg.Temperature, cfg.TopP, cfg.Stop, cfg.ReasoningEffort, cfg.MaxCompletionTokens, cfg.SafetyThreshold})
+	return string(b)
+}
+
+// WithCache returns llm answering from c when possible, keyed by the
+// CacheSettings of cfg. Only successful responses are cached.
+func WithCache(llm LLM, c Cache, cfg Config) LLM {
+	cached := &cachedLLM{LLM: llm, cache: c, settings: CacheSettings(cfg)}
+	if s, ok := llm.(Streamer); ok {
+		return &cachedStreamer{cachedLLM: cached, streamer: s}
+	}
+	return cached
+}
+
+type cachedLLM struct {
+	LLM
+	cache    Cache
+	settings string
+}
+
+func (c *cachedLLM) key(query string) string {
+	return CacheKey(c.Name(), c.Model(), c.settings, query)
+}
+
+func (c *cachedLLM) Analyze(ctx context.Context, query string) (string, error) {
+	key := c.key(query)
+	if resp, ok := c.cache.Get(key); ok {
+		return resp, nil
+	}
+	resp, err := c.LLM.Analyze(ctx, query)
+	if err != nil {
+		return "", err
+	}
+	c.cache.Set(key, resp)
+	return resp, nil
+}
+
+type cachedStreamer struct {
+	*cachedLLM
+	streamer Streamer
+}
+
+// AnalyzeStream implements Streamer. A cached answer is sent as a single
+// chunk; a streamed answer is cached once it completes.
+func (c *cachedStreamer) AnalyzeStream(ctx context.Context, query string) (<-chan StreamChunk, error) {
+	key := c.key(query)
+	if resp, ok := c.cache.Get(key); ok {
+		ch := make(chan StreamChunk, 1)
+		ch <- StreamChunk{Text: responseText(resp)}
+		close(ch)
+		return ch, nil
+	}
+	chunks, err := c.streamer.AnalyzeStream(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan StreamChunk)
+	go func() {
+		defer close(out)
+		var answer strings.Builder
+		for chunk := range chunks {
+			if !send(ctx, out, chunk) || chunk.Err != nil {
+				return
+			}
+			answer.WriteString(chunk.Text)
+		}
+		if resp, err := lightspeedShape(answer.String(), 0, 0); err == nil {
+			c.cache.Set(key, resp)
+		}
+	}()
+	return out, nil
+}
+
+// responseText returns the answer text of a response in the Lightspeed
+// shape
+func responseText(resp string) string {
+	var r struct {
+		Response string `json:"response"`
+	}
+	if err := json.Unmarshal([]byte(resp), &r); err != nil {
+		return resp
+	}
+	return r.Response
+}
+
+type cacheEntry struct {
+	Created  time.Time `json:"created"`
+	Response string    `json:"response"`
+}
+
+func (e cacheEntry) fresh(ttl time.Duration) bool {
+	return time.Since(e.Created) < ttl
+}
+
+// MemoryCache is a Cache kept in process memory
+type MemoryCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// NewMemoryCache returns a MemoryCache whose entries expire after ttl
+func NewMemoryCache(ttl time.Duration) *MemoryCache {
+	return &MemoryCache{ttl: ttl, entries: map[string]cacheEntry{}}
+}
+
+// Get implements Cache
+func (m *MemoryCache) Get(key string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return "", false
+	}
+	if !e.fresh(m.ttl) {
+		delete(m.entries, key)
+		return "", false
+	}
+	return e.Response, true
+}
+
+// Set implements Cache
+func (m *MemoryCache) Set(key, response string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = cacheEntry{Created: time.Now(), Response: response}
+}
+
+// DiskCache is a Cache stored as one file per entry, so it is shared by
+// separate CLI invocations. Write failures are ignored; the cache is an
+// optimization only.
+type DiskCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewDiskCache returns a DiskCache in dir whose entries expire after ttl
+func NewDiskCache(dir string, ttl time.Duration) *DiskCache {
+	return &DiskCache{dir: dir, ttl: ttl}
+}
+
+// DefaultCacheDir returns the per-user cache directory of tekton-assist
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "tekton-assist")
+}
+
+// Get implements Cache
+func (d *DiskCache) Get(key string) (string, bool) {
+	b, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return "", false
+	}
+	var e cacheEntry
+	if json.Unmarshal(b, &e) != nil || !e.fresh(d.ttl) {
+		_ = os.Remove(d.path(key))
+		return "", false
+	}
+	return e.Response, true
+}
+
+// Set implements Cache
+func (d *DiskCache) Set(key, response string) {
+	// Responses quote log content, so keep them private to the user
+	if err := os.MkdirAll(d.dir, 0o700); err != nil {
+		return
+	}
+	b, err := json.Marshal(cacheEntry{Created: time.Now(), Response: response})
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(d.dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(b)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), d.path(key)); err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+func (d *DiskCache) path(key string) string {
+	return filepath.Join(d.dir, key+".json")
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCacheSettings(t *testing.T) {
+	zero, one := 0.0, 1.0
+	base := Config{SystemPrompt: "s", Temperature: &zero}
+	same := CacheKey("p", "m", CacheSettings(base), "q 2025-06-01T10:00:00Z")
+	for name, cfg := range map[string]Config{
+		"system prompt":    {SystemPrompt: "other", Temperature: &zero},
+		"temperature":      {SystemPrompt: "s", Temperature: &one},
+		"max tokens":       {SystemPrompt: "s", Temperature: &zero, MaxTokens: 100},
+		"reasoning effort": {SystemPrompt: "s", Temperature: &zero, ReasoningEffort: "high"},
+	} {
+		if CacheKey("p", "m", CacheSettings(cfg), "q 2025-06-01T10:00:00Z") == same {
+			t.Errorf("changing the %s keeps the cache key", name)
+		}
+	}
+	if CacheKey("p", "m", CacheSettings(base), "q 2025-06-02T11:30:00Z") != same {
+		t.Error("expected timestamps to be masked from the cache key")
+	}
+}
+
+// streamingLLM answers every query with text in two chunks
+type streamingLLM struct {
+	text  string
+	calls int
+}
+
+func (s *streamingLLM) Analyze(ctx context.Context, query string) (string, error) {
+	s.calls++
+	return lightspeedShape(s.text, 0, 0)
+}
+
+func (s *streamingLLM) AnalyzeStream(ctx context.Context, query string) (<-chan StreamChunk, error) {
+	s.calls++
+	ch := make(chan StreamChunk, 2)
+	ch <- StreamChunk{Text: s.text[:3]}
+	ch <- StreamChunk{Text: s.text[3:]}
+	close(ch)
+	return ch, nil
+}
+
+func (s *streamingLLM) Name() string  { return "fake" }
+func (s *streamingLLM) Model() string { return "" }
+
+func TestWithCache_Streams(t *testing.T) {
+	inner := &streamingLLM{text: "out of memory"}
+	llm := WithCache(inner, NewMemoryCache(time.Hour), Config{})
+	for i := 0; i < 2; i++ {
+		var out bytes.Buffer
+		_, streamed, err := Stream(context.Background(), llm, "q", &out)
+		if err != nil || !streamed || out.String() != "out of memory" {
+			t.Fatalf("call %d: streamed %v, output %q, error %v", i+1, streamed, out.String(), err)
+		}
+	}
+	if inner.calls != 1 {
+		t.Fatalf("expected the second answer from the cache, got %d provider calls", inner.calls)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"math"
+	"strings"
+)
+
+// ConfidenceKey is the top-level response field holding Confidence. It
+// differs from the numeric confidence field of the answer itself.
+const ConfidenceKey = "confidence_assessment"
+
+// Sources of a confidence score
+const (
+	ConfidenceFromModel     = "model"
+	ConfidenceFromHeuristic = "heuristic"
+)
+
+// Confidence levels, so UIs can de-emphasize weak diagnoses
+const (
+	ConfidenceLow    = "low"
+	ConfidenceMedium = "medium"
+	ConfidenceHigh   = "high"
+)
+
+// maxHeuristicConfidence keeps guesses below what a model can claim
+const maxHeuristicConfidence = 0.8
+
+// Confidence estimates how reliable a diagnosis is
+type Confidence struct {
+	Score  float64 `json:"score"`
+	Level  string  `json:"level"`
+	Source string  `json:"source"`
+}
+
+// Assess sets r.Confidence. The model's own score is used when the answer
+// validated as a structured analysis that states one; otherwise it is
+// estimated from the
+// completeness of the answer, whether it matches a known failure from the
+// runbooks and, when evidence (the log sent) is given, whether the log
+// contains an error at all.
+func (r *Result) Assess(evidence string) {
+	if _, stated := answerObject(r.Response)["confidence"].(float64); r.Structured != nil && stated {
+		r.Confidence = newConfidence(r.Structured.Confidence, ConfidenceFromModel)
+		return
+	}
+
+	answer := ParseAnswer(r.Response)
+	score := 0.3
+	if answer.Analysis != "" {
+		score += 0.1
+	}
+	if len(answer.Solutions) > 0 {
+		score += 0.1
+	}
+	if evidence != "" {
+		if hasErrorMarker(evidence) {
+			score += 0.15
+		} else {
+			score -= 0.15
+		}
+	}
+	if len(RetrieveRunbooks(evidence+"\n"+answer.Summary+"\n"+answer.Analysis, 1)) > 0 {
+		score += 0.15
+	}
+	r.Confidence = newConfidence(math.Min(score, maxHeuristicConfidence), ConfidenceFromHeuristic)
+}
+
+func newConfidence(score float64, source string) *Confidence {
+	score = math.Max(0, math.Min(1, score))
+	level := ConfidenceHigh
+	switch {
+	case score < 0.4:
+		level = ConfidenceLow
+	case score < 0.7:
+		level = ConfidenceMedium
+	}
+	return &Confidence{Score: math.Round(score*100) / 100, Level: level, Source: source}
+}
+
+func hasErrorMarker(text string) bool {
+	lower := strings.ToLower(text)
+	for _, marker := range errorMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssess(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   string
+		wantSource string
+		wantScore  float64
+	}{
+		{
+			name:       "model score",
+			response:   `{"response":"{\"root_cause\":\"x\",\"category\":\"auth\",\"solutions\":[\"y\"],\"confidence\":0.9}"}`,
+			wantSource: ConfidenceFromModel,
+			wantScore:  0.9,
+		},
+		{
+			name:       "structured without a score",
+			response:   `{"response":"{\"root_cause\":\"x\",\"category\":\"auth\",\"solutions\":[\"y\"]}"}`,
+			wantSource: ConfidenceFromHeuristic,
+			wantScore:  0.4,
+		},
+		{
+			name:       "prose",
+			response:   `{"response":"something failed"}`,
+			wantSource: ConfidenceFromHeuristic,
+			wantScore:  0.3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Result{Response: tt.response, Structured: structured(tt.response)}
+			r.Assess("")
+			if c := r.Confidence; c.Source != tt.wantSource || c.Score != tt.wantScore {
+				t.Fatalf("Assess() = %+v, want %s score %.2f", c, tt.wantSource, tt.wantScore)
+			}
+			if !strings.Contains(r.JSON(), `"`+ConfidenceKey+`":{"score":`) {
+				t.Fatalf("expected the assessment under %s, got %s", ConfidenceKey, r.JSON())
+			}
+		})
+	}
+}
+
+func TestResult_Confidence(t *testing.T) {
+	res := &Result{Response: `{"response":"{\"root_cause\":\"x\",\"category\":\"auth\",\"solutions\":[\"y\"],\"confidence\":0.9}"}`}
+	res.Structured, _ = ParseStructured(res.Response)
+	res.Assess("")
+	if c := res.Confidence; c.Source != ConfidenceFromModel || c.Level != ConfidenceHigh || c.Score != 0.9 {
+		t.Fatalf("expected the model's confidence, got %+v", c)
+	}
+
+	weak := &Result{Response: `{"response":"It is unclear why the build stopped."}`}
+	weak.Assess("step started\nstep finished")
+	strong := &Result{Response: `{"response":"The step was OOMKilled.","analysis":"The container exceeded its memory limit (exit code 137).","solutions":["raise computeResources.limits.memory"]}`}
+	strong.Assess("step-build terminated: exit code 137, reason OOMKilled")
+	if weak.Confidence.Level != ConfidenceLow || strong.Confidence.Score <= weak.Confidence.Score || strong.Confidence.Source != ConfidenceFromHeuristic {
+		t.Fatalf("unexpected heuristic confidence: weak %+v, strong %+v", weak.Confidence, strong.Confidence)
+	}
+	if !strings.Contains(strong.JSON(), `"confidence_assessment":{"score":`) {
+		t.Fatalf("expected confidence in the JSON response, got %s", strong.JSON())
+	}
+}
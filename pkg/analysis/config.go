@@ -0,0 +1,90 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ConfigError reports a single invalid Config field
+type ConfigError struct {
+	Field   string
+	Message string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Message)
+}
+
+// ValidateConfig checks cfg and returns every problem found, joined with
+// errors.Join, so callers can report all of them at once. Each joined error
+// is a *ConfigError.
+func ValidateConfig(cfg Config) error {
+	var errs []error
+
+	if r, ok := lookup(cfg.Provider); !ok {
+		errs = append(errs, &ConfigError{Field: "provider", Message: fmt.Sprintf("unknown provider %q", cfg.Provider)})
+	} else if r.validate != nil {
+		errs = append(errs, r.validate(cfg)...)
+	}
+
+	if cfg.BaseURL != "" {
+		u, err := url.Parse(cfg.BaseURL)
+		switch {
+		case err != nil:
+			errs = append(errs, &ConfigError{Field: "base URL", Message: err.Error()})
+		case u.Scheme != "http" && u.Scheme != "https":
+			errs = append(errs, &ConfigError{Field: "base URL", Message: fmt.Sprintf("scheme must be http or https, got %q", cfg.BaseURL)})
+		case u.Host == "":
+			errs = append(errs, &ConfigError{Field: "base URL", Message: fmt.Sprintf("missing host in %q", cfg.BaseURL)})
+		}
+	}
+
+	if cfg.CompletionsPath != "" && !strings.HasPrefix(cfg.CompletionsPath, "/") {
+		errs = append(errs, &ConfigError{Field: "completions path", Message: fmt.Sprintf("must start with /, got %q", cfg.CompletionsPath)})
+	}
+	if _, err := rootCAs(cfg); err != nil {
+		errs = append(errs, &ConfigError{Field: "CA bundle", Message: err.Error()})
+	}
+
+	if cfg.Timeout < 0 {
+		errs = append(errs, &ConfigError{Field: "timeout", Message: "must not be negative"})
+	}
+	if cfg.CacheTTL < 0 {
+		errs = append(errs, &ConfigError{Field: "cache TTL", Message: "must not be negative"})
+	}
+	errs = append(errs, validateSampling(cfg)...)
+	errs = append(errs, validateSafetyThreshold(cfg)...)
+	tc := cfg.Transport
+	for _, f := range []struct {
+		name     string
+		negative bool
+	}{
+		{"max idle connections", tc.MaxIdleConns < 0},
+		{"max idle connections per host", tc.MaxIdleConnsPerHost < 0},
+		{"max connections per host", tc.MaxConnsPerHost < 0},
+		{"idle connection timeout", tc.IdleConnTimeout < 0},
+		{"keep-alive", tc.KeepAlive < 0},
+	} {
+		if f.negative {
+			errs = append(errs, &ConfigError{Field: f.name, Message: "must not be negative"})
+		}
+	}
+
+	return errors.Join(errs...)
+}
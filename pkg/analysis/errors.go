@@ -0,0 +1,135 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrorCode is a machine-readable failure category returned by providers
+type ErrorCode string
+
+const (
+	// CodeBadRequest means the provider rejected the request payload
+	CodeBadRequest ErrorCode = "BadRequest"
+	// CodeUnauthorized means the credentials were missing or invalid
+	CodeUnauthorized ErrorCode = "Unauthorized"
+	// CodeForbidden means the credentials lack the required permissions
+	CodeForbidden ErrorCode = "Forbidden"
+	// CodeNotFound means the endpoint or referenced resource does not exist
+	CodeNotFound ErrorCode = "NotFound"
+	// CodeRateLimited means the provider throttled the request
+	CodeRateLimited ErrorCode = "RateLimited"
+	// CodeUnavailable means the provider is down or timed out upstream
+	CodeUnavailable ErrorCode = "Unavailable"
+	// CodeInternal means the provider failed while handling the request
+	CodeInternal ErrorCode = "Internal"
+	// CodeUnknown is used when no other category applies
+	CodeUnknown ErrorCode = "Unknown"
+)
+
+// errorCodes is the closed set of ErrorCode values
+var errorCodes = map[ErrorCode]bool{
+	CodeBadRequest: true, CodeUnauthorized: true, CodeForbidden: true, CodeNotFound: true,
+	CodeRateLimited: true, CodeUnavailable: true, CodeInternal: true, CodeUnknown: true,
+}
+
+// APIError describes a non-2xx response from a provider. Fields follow
+// RFC 7807 (application/problem+json) so clients can branch on Code.
+type APIError struct {
+	Provider string    `json:"-"`
+	Code     ErrorCode `json:"code"`
+	Type     string    `json:"type,omitempty"`
+	Title    string    `json:"title,omitempty"`
+	Status   int       `json:"status"`
+	Detail   string    `json:"detail,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	detail := e.Detail
+	if detail == "" {
+		detail = e.Title
+	}
+	return fmt.Sprintf("%s returned %d (%s): %s", e.Provider, e.Status, e.Code, detail)
+}
+
+// CodeForStatus maps an HTTP status code onto an ErrorCode
+func CodeForStatus(status int) ErrorCode {
+	switch {
+	case status == http.StatusBadRequest || status == http.StatusUnprocessableEntity:
+		return CodeBadRequest
+	case status == http.StatusUnauthorized:
+		return CodeUnauthorized
+	case status == http.StatusForbidden:
+		return CodeForbidden
+	case status == http.StatusNotFound:
+		return CodeNotFound
+	case status == http.StatusTooManyRequests:
+		return CodeRateLimited
+	case status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout:
+		return CodeUnavailable
+	case status >= 500:
+		return CodeInternal
+	default:
+		return CodeUnknown
+	}
+}
+
+// newAPIError builds an APIError from a response, decoding problem+json
+// bodies when present and falling back to the raw body text. Code stays
+// within the ErrorCode set: a server code is only used when it is one of
+// them, and codes of other types such as the numeric code of some
+// OpenAI-compatible servers are ignored.
+func newAPIError(provider string, status int, body []byte) *APIError {
+	apiErr := &APIError{
+		Provider: provider,
+		Code:     CodeForStatus(status),
+		Status:   status,
+		Title:    http.StatusText(status),
+	}
+
+	var problem struct {
+		Type   string          `json:"type"`
+		Title  string          `json:"title"`
+		Detail json.RawMessage `json:"detail"`
+		Code   json.RawMessage `json:"code"`
+	}
+	if json.Unmarshal(body, &problem) == nil {
+		if problem.Type != "" {
+			apiErr.Type = problem.Type
+		}
+		if problem.Title != "" {
+			apiErr.Title = problem.Title
+		}
+		var code ErrorCode
+		if json.Unmarshal(problem.Code, &code) == nil && errorCodes[code] {
+			apiErr.Code = code
+		}
+		// detail is a string in RFC 7807 but an object in Lightspeed/FastAPI errors
+		var s string
+		if json.Unmarshal(problem.Detail, &s) == nil {
+			apiErr.Detail = s
+		} else if len(problem.Detail) > 0 {
+			apiErr.Detail = string(problem.Detail)
+		}
+	}
+	if apiErr.Detail == "" {
+		apiErr.Detail = strings.TrimSpace(string(body))
+	}
+	return apiErr
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import "testing"
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantCode   ErrorCode
+		wantTitle  string
+		wantDetail string
+	}{
+		{
+			name:       "problem json",
+			status:     429,
+			body:       `{"type":"about:blank","title":"Slow down","detail":"quota exceeded"}`,
+			wantCode:   CodeRateLimited,
+			wantTitle:  "Slow down",
+			wantDetail: "quota exceeded",
+		},
+		{
+			name:       "known server code",
+			status:     500,
+			body:       `{"code":"Unavailable","detail":"model loading"}`,
+			wantCode:   CodeUnavailable,
+			wantTitle:  "Internal Server Error",
+			wantDetail: "model loading",
+		},
+		{
+			name:       "unknown server code",
+			status:     400,
+			body:       `{"code":"context_length_exceeded","title":"Too long"}`,
+			wantCode:   CodeBadRequest,
+			wantTitle:  "Too long",
+			wantDetail: `{"code":"context_length_exceeded","title":"Too long"}`,
+		},
+		{
+			name:       "numeric code",
+			status:     400,
+			body:       `{"object":"error","code":400,"type":"BadRequestError","detail":"max_tokens is too large"}`,
+			wantCode:   CodeBadRequest,
+			wantTitle:  "Bad Request",
+			wantDetail: "max_tokens is too large",
+		},
+		{
+			name:       "object detail",
+			status:     422,
+			body:       `{"detail":{"msg":"field required"}}`,
+			wantCode:   CodeBadRequest,
+			wantTitle:  "Unprocessable Entity",
+			wantDetail: `{"msg":"field required"}`,
+		},
+		{
+			name:       "plain text",
+			status:     503,
+			body:       "upstream connect error\n",
+			wantCode:   CodeUnavailable,
+			wantTitle:  "Service Unavailable",
+			wantDetail: "upstream connect error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newAPIError("p", tt.status, []byte(tt.body))
+			if got.Code != tt.wantCode || got.Title != tt.wantTitle || got.Detail != tt.wantDetail || got.Status != tt.status {
+				t.Fatalf("newAPIError() = %+v, want code %s, title %q, detail %q", got, tt.wantCode, tt.wantTitle, tt.wantDetail)
+			}
+		})
+	}
+}
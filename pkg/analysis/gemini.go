@@ -0,0 +1,205 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const (
+	// ProviderGemini selects the Google Gemini backend
+	ProviderGemini = "gemini"
+
+	// DefaultGeminiURL is the Generative Language API endpoint
+	DefaultGeminiURL = "https://generativelanguage.googleapis.com"
+	// DefaultGeminiModel is used when no model is configured
+	DefaultGeminiModel = "gemini-2.5-flash"
+	// GeminiAPIKeyEnv is read when Config.APIKey is empty
+	GeminiAPIKeyEnv = "GEMINI_API_KEY"
+)
+
+// geminiHarmCategories are the categories a SafetyThreshold applies to
+var geminiHarmCategories = []string{
+	"HARM_CATEGORY_HARASSMENT",
+	"HARM_CATEGORY_HATE_SPEECH",
+	"HARM_CATEGORY_SEXUALLY_EXPLICIT",
+	"HARM_CATEGORY_DANGEROUS_CONTENT",
+}
+
+// GeminiSafetyThresholds are the accepted values of Config.SafetyThreshold
+var GeminiSafetyThresholds = []string{"BLOCK_NONE", "BLOCK_ONLY_HIGH", "BLOCK_MEDIUM_AND_ABOVE", "BLOCK_LOW_AND_ABOVE", "OFF"}
+
+// validateSafetyThreshold checks that a safety threshold is only set for
+// gemini, to a value it accepts
+func validateSafetyThreshold(cfg Config) []error {
+	t := cfg.SafetyThreshold
+	switch {
+	case t == "":
+		return nil
+	case cfg.Provider != ProviderGemini:
+		return []error{&ConfigError{Field: "safety threshold", Message: "only sent by " + ProviderGemini}}
+	case !slices.Contains(GeminiSafetyThresholds, t):
+		return []error{&ConfigError{Field: "safety threshold", Message: fmt.Sprintf("must be one of %s, got %q", strings.Join(GeminiSafetyThresholds, ", "), t)}}
+	}
+	return nil
+}
+
+func init() {
+	Register(ProviderGemini, func(cfg Config) (LLM, error) {
+		return NewGeminiLLM(cfg), nil
+	})
+	RegisterValidator(ProviderGemini, func(cfg Config) []error {
+		if apiKey(cfg, GeminiAPIKeyEnv) == "" {
+			return []error{&ConfigError{Field: "API key", Message: "gemini requires an API key (set " + GeminiAPIKeyEnv + ")"}}
+		}
+		return nil
+	})
+}
+
+// GeminiLLM talks to the Gemini generateContent API
+type GeminiLLM struct {
+	baseURL         string
+	apiKey          string
+	model           string
+	safetyThreshold string
+	systemPrompt    string
+	sampling        sampling
+	client          *http.Client
+}
+
+// NewGeminiLLM creates a Gemini backed LLM
+func NewGeminiLLM(cfg Config) *GeminiLLM {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultGeminiURL
+	}
+	model := cfg.Model
+	if model == "" {
+		model = DefaultGeminiModel
+	}
+	return &GeminiLLM{
+		baseURL:         baseURL,
+		apiKey:          apiKey(cfg, GeminiAPIKeyEnv),
+		model:           model,
+		safetyThreshold: cfg.SafetyThreshold,
+		systemPrompt:    cfg.SystemPrompt,
+		sampling:        newSampling(cfg),
+		client:          newHTTPClient(cfg),
+	}
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiSafetySetting struct {
+	Category  string `json:"category"`
+	Threshold string `json:"threshold"`
+}
+
+type geminiGenerationConfig struct {
+	MaxOutputTokens int      `json:"maxOutputTokens,omitempty"`
+	Temperature     *float64 `json:"temperature,omitempty"`
+	TopP            *float64 `json:"topP,omitempty"`
+	StopSequences   []string `json:"stopSequences,omitempty"`
+}
+
+type geminiRequest struct {
+	SystemInstruction *geminiContent          `json:"systemInstruction,omitempty"`
+	Contents          []geminiContent         `json:"contents"`
+	SafetySettings    []geminiSafetySetting   `json:"safetySettings,omitempty"`
+	GenerationConfig  *geminiGenerationConfig `json:"generationConfig,omitempty"`
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	PromptFeedback struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+// Analyze sends the query to Gemini and returns the answer in the
+// Lightspeed response shape
+func (g *GeminiLLM) Analyze(ctx context.Context, query string) (string, error) {
+	req := geminiRequest{
+		Contents: []geminiContent{{Role: "user", Parts: []geminiPart{{Text: query}}}},
+	}
+	if g.systemPrompt != "" {
+		req.SystemInstruction = &geminiContent{Parts: []geminiPart{{Text: g.systemPrompt}}}
+	}
+	if s := g.sampling; s.isSet() {
+		req.GenerationConfig = &geminiGenerationConfig{
+			MaxOutputTokens: s.maxTokens,
+			Temperature:     s.temperature,
+			TopP:            s.topP,
+			StopSequences:   s.stop,
+		}
+	}
+	if g.safetyThreshold != "" {
+		for _, c := range geminiHarmCategories {
+			req.SafetySettings = append(req.SafetySettings, geminiSafetySetting{Category: c, Threshold: g.safetyThreshold})
+		}
+	}
+
+	endpoint := joinURL(g.baseURL, "/v1beta/models/"+url.PathEscape(g.model)+":generateContent")
+	var resp geminiResponse
+	if err := postJSON(ctx, g.client, ProviderGemini, endpoint, map[string]string{"x-goog-api-key": g.apiKey}, req, &resp); err != nil {
+		return "", err
+	}
+
+	if resp.PromptFeedback.BlockReason != "" {
+		return "", fmt.Errorf("gemini blocked the prompt: %s", resp.PromptFeedback.BlockReason)
+	}
+	if len(resp.Candidates) == 0 {
+		return "", fmt.Errorf("gemini returned no candidates")
+	}
+
+	var text strings.Builder
+	for _, p := range resp.Candidates[0].Content.Parts {
+		text.WriteString(p.Text)
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("gemini returned an empty answer (finish reason %s)", resp.Candidates[0].FinishReason)
+	}
+
+	return lightspeedShape(text.String(), resp.UsageMetadata.PromptTokenCount, resp.UsageMetadata.CandidatesTokenCount)
+}
+
+// Name implements LLM
+func (g *GeminiLLM) Name() string {
+	return ProviderGemini
+}
+
+// Model implements LLM
+func (g *GeminiLLM) Model() string {
+	return g.model
+}
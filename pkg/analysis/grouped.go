@@ -0,0 +1,98 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MaxGroupedRuns bounds the runs diagnosed by one grouped query, so the
+// answer fits the output limit of common models
+const MaxGroupedRuns = 20
+
+// RunID names a run of a grouped query
+type RunID struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (r RunID) String() string {
+	return fmt.Sprintf("%s %s/%s", r.Kind, r.Namespace, r.Name)
+}
+
+// GroupedQuery builds one query diagnosing several failed runs, e.g. after
+// a bad merge, and asking for failures with a shared root cause to be
+// grouped
+func GroupedQuery(runs []RunID) string {
+	var b strings.Builder
+	b.WriteString("The following Tekton runs failed, possibly for a shared reason such as a bad merge or a registry outage. " +
+		"Diagnose why each of them failed:\n")
+	for i, r := range runs {
+		fmt.Fprintf(&b, "%d. %s '%s' in namespace '%s'\n", i+1, r.Kind, r.Name, r.Namespace)
+	}
+	b.WriteString("\nRuns that fail for the same root cause should share a group label. " +
+		"Respond as a JSON object with a single field diagnoses: an array with one object per run, in the order above, with fields: " +
+		"index (the number of the run above), group (a short label shared by runs with the same root cause), " +
+		"response (a brief summary), analysis (string), root_cause (one sentence), category (one of " + categoryList() + "), " +
+		"solutions (array of strings), confidence (number from 0 to 1, how sure you are of the root cause).")
+	return b.String()
+}
+
+// SplitGrouped returns the diagnosis of each of runs from the answer to a
+// GroupedQuery, in the order of runs, each as a Result of its own with its
+// group label. Runs the answer does not cover are nil. The usage of the
+// call stays on result.
+func SplitGrouped(result *Result, runs []RunID) ([]*Result, []string, error) {
+	answer := answerObject(result.Response)
+	items, ok := answer["diagnoses"].([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("the answer has no diagnoses array")
+	}
+
+	out := make([]*Result, len(runs))
+	groups := make([]string, len(runs))
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		i := -1
+		if n, ok := obj["index"].(float64); ok && int(n) >= 1 && int(n) <= len(runs) {
+			i = int(n) - 1
+		}
+		if i < 0 || out[i] != nil {
+			continue
+		}
+		b, err := json.Marshal(obj)
+		if err != nil {
+			return nil, nil, err
+		}
+		resp, err := lightspeedShape(string(b), 0, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		meta := result.Metadata
+		meta.Usage = nil
+		r := &Result{Response: resp, Structured: structured(resp), Metadata: meta}
+		r.Assess("")
+		r.checkSnippets()
+		out[i] = r
+		groups[i], _ = obj["group"].(string)
+	}
+	return out, groups, nil
+}
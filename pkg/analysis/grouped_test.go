@@ -0,0 +1,75 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSplitGrouped(t *testing.T) {
+	runs := []RunID{{"TaskRun", "ns", "a"}, {"TaskRun", "ns", "b"}, {"TaskRun", "ns", "c"}}
+	tests := []struct {
+		name       string
+		answer     string
+		wantGroups []string
+		wantErr    bool
+	}{
+		{
+			name:       "in order",
+			answer:     `{"diagnoses":[{"index":1,"group":"registry"},{"index":2,"group":"registry"},{"index":3,"group":"quota"}]}`,
+			wantGroups: []string{"registry", "registry", "quota"},
+		},
+		{
+			name:       "out of order and missing",
+			answer:     `{"diagnoses":[{"index":3,"group":"quota"},{"index":1,"group":"registry"}]}`,
+			wantGroups: []string{"registry", "", "quota"},
+		},
+		{
+			name:       "duplicate and out of range indexes",
+			answer:     `{"diagnoses":[{"index":1,"group":"first"},{"index":1,"group":"second"},{"index":0},{"index":4},{"group":"none"},"text"]}`,
+			wantGroups: []string{"first", "", ""},
+		},
+		{
+			name:    "no diagnoses",
+			answer:  `{"response":"all runs failed"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := json.Marshal(map[string]string{"response": tt.answer})
+			usage := &Usage{InputTokens: 10}
+			results, groups, err := SplitGrouped(&Result{Response: string(resp), Metadata: Metadata{Usage: usage}}, runs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitGrouped() error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			for i, want := range tt.wantGroups {
+				if groups[i] != want {
+					t.Errorf("group of run %d = %q, want %q", i+1, groups[i], want)
+				}
+				if want == "" && results[i] != nil {
+					t.Errorf("run %d is not diagnosed by the answer, got %+v", i+1, results[i])
+				}
+				if r := results[i]; r != nil && r.Metadata.Usage != nil {
+					t.Errorf("run %d carries the usage of the grouped call", i+1)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultLightspeedURL is used when no base URL is configured
+const DefaultLightspeedURL = "https://localhost:8443"
+
+func init() {
+	Register(ProviderLightspeed, func(cfg Config) (LLM, error) {
+		return NewLightspeedLLM(cfg), nil
+	})
+}
+
+// LightspeedLLM talks to the OpenShift Lightspeed /v1/query endpoint
+type LightspeedLLM struct {
+	baseURL      string
+	token        string
+	model        string
+	systemPrompt string
+	client       *http.Client
+}
+
+// NewLightspeedLLM creates a Lightspeed backed LLM
+func NewLightspeedLLM(cfg Config) *LightspeedLLM {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultLightspeedURL
+	}
+
+	return &LightspeedLLM{
+		baseURL:      baseURL,
+		token:        cfg.Token,
+		model:        cfg.Model,
+		systemPrompt: cfg.SystemPrompt,
+		client:       newHTTPClient(cfg),
+	}
+}
+
+// Analyze sends the query to Lightspeed and returns the raw JSON response
+func (l *LightspeedLLM) Analyze(ctx context.Context, query string) (string, error) {
+	payload := map[string]interface{}{
+		"query": query,
+	}
+	if l.model != "" {
+		payload["model"] = l.model
+	}
+	if l.systemPrompt != "" {
+		payload["system_prompt"] = l.systemPrompt
+	}
+	bodyBytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, joinURL(l.baseURL, "/v1/query"), bytes.NewReader(bodyBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if l.token != "" {
+		req.Header.Set("Authorization", "Bearer "+l.token)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request to Lightspeed failed: %w", err)
+	}
+	defer safeClose(resp.Body)
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", newAPIError(ProviderLightspeed, resp.StatusCode, respBody)
+	}
+
+	return string(respBody), nil
+}
+
+// AnalyzeStream implements Streamer using the /v1/streaming_query endpoint
+// in plain text mode
+func (l *LightspeedLLM) AnalyzeStream(ctx context.Context, query string) (<-chan StreamChunk, error) {
+	payload := map[string]interface{}{
+		"query":      query,
+		"media_type": "text/plain",
+	}
+	if l.model != "" {
+		payload["model"] = l.model
+	}
+	if l.systemPrompt != "" {
+		payload["system_prompt"] = l.systemPrompt
+	}
+	headers := map[string]string{"accept": "text/plain"}
+	if l.token != "" {
+		headers["Authorization"] = "Bearer " + l.token
+	}
+
+	body, err := postStream(ctx, l.client, ProviderLightspeed, joinURL(l.baseURL, "/v1/streaming_query"), headers, payload)
+	if err != nil {
+		return nil, err
+	}
+	return streamText(ctx, body), nil
+}
+
+// Name implements LLM
+func (l *LightspeedLLM) Name() string {
+	return ProviderLightspeed
+}
+
+// Model implements LLM
+func (l *LightspeedLLM) Model() string {
+	return l.model
+}
+
+// --- helpers ---
+
+func joinURL(base, path string) string {
+	if base == "" {
+		return path
+	}
+	if len(base) > 0 && base[len(base)-1] == '/' {
+		base = base[:len(base)-1]
+	}
+	if len(path) > 0 && path[0] == '/' {
+		return base + path
+	}
+	return base + "/" + path
+}
+
+func safeClose(c io.Closer) {
+	_ = c.Close()
+}
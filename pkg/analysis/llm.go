@@ -0,0 +1,174 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ProviderLightspeed selects the OpenShift Lightspeed backend.
+const ProviderLightspeed = "lightspeed"
+
+// LLM is implemented by every backend able to answer a diagnosis query.
+// Analyze returns the raw Lightspeed response body so callers can render
+// provider specific fields (references, token usage) themselves; other
+// providers return their answer in the same JSON shape ("response",
+// "input_tokens", "output_tokens").
+type LLM interface {
+	Analyze(ctx context.Context, query string) (string, error)
+	// Name returns the provider name recorded in diagnosis metadata
+	Name() string
+	// Model returns the configured model, or "" for the provider default
+	Model() string
+}
+
+// Config holds the settings shared by all providers
+type Config struct {
+	Provider string
+	Model    string
+	// BaseURL overrides the provider endpoint
+	BaseURL string
+	// Token is the bearer token used by Lightspeed
+	Token string
+	// APIKey authenticates to hosted providers; when empty it is read
+	// from the provider specific environment variable
+	APIKey      string
+	InsecureTLS bool
+	Timeout     time.Duration
+	Transport   TransportConfig
+
+	// APIVersion selects the Azure OpenAI data-plane api-version
+	APIVersion string
+	// MaxTokens caps the completion length (0 keeps the provider default)
+	MaxTokens int
+	// Temperature, TopP and Stop tune generation; nil or empty keeps the
+	// provider default
+	Temperature *float64
+	TopP        *float64
+	Stop        []string
+	// ReasoningEffort (minimal, low, medium or high) and
+	// MaxCompletionTokens configure OpenAI-style reasoning models such as
+	// o4-mini. MaxCompletionTokens bounds reasoning and answer together and
+	// replaces MaxTokens, which these models reject.
+	ReasoningEffort     string
+	MaxCompletionTokens int
+	// SystemPrompt is sent as the system message by chat-style providers,
+	// as the system instruction to Gemini and as system_prompt to
+	// Lightspeed
+	SystemPrompt string
+
+	// SafetyThreshold applies a Gemini safety threshold (e.g.
+	// BLOCK_ONLY_HIGH) to all harm categories
+	SafetyThreshold string
+
+	// Headers are extra HTTP headers sent with every provider request
+	Headers map[string]string
+	// CompletionsPath overrides the chat completions path of
+	// openai-compatible servers
+	CompletionsPath string
+
+	// CacheTTL enables response caching for this long (0 disables it)
+	CacheTTL time.Duration
+	// CacheDir stores the cache on disk; when empty it is kept in memory
+	CacheDir string
+
+	// Pricing estimates the cost of each call from its token usage
+	Pricing Pricing
+
+	// Patterns are an organization's known failures, tried by the rules
+	// provider before BuiltinRules
+	Patterns []Rule
+
+	// Redactor masks credentials and personal data in every query; nil
+	// applies the built-in rules and NoRedaction disables masking
+	Redactor *Redactor
+	// SafeMode replaces namespace, run, image and host names with tokens
+	// before queries reach an external provider and restores them in the
+	// answer. Lightspeed runs in the cluster and reads runs by name, so it
+	// is exempt.
+	SafeMode bool
+}
+
+// New validates cfg and returns the LLM of the registered provider named by
+// cfg.Provider, or Lightspeed when it is empty. Invalid configuration is
+// reported via ValidateConfig.
+func New(cfg Config) (LLM, error) {
+	if err := ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+	// The system prompt is sent with every query, so it is redacted and
+	// pseudonymized like them
+	if cfg.Redactor != NoRedaction {
+		cfg.SystemPrompt, _ = cfg.Redactor.Redact(cfg.SystemPrompt)
+	}
+	var pseudonyms *Pseudonymizer
+	if cfg.SafeMode && cfg.Provider != "" && cfg.Provider != ProviderLightspeed && cfg.Provider != ProviderRules {
+		pseudonyms = NewPseudonymizer()
+		cfg.SystemPrompt = pseudonyms.Pseudonymize(cfg.SystemPrompt)
+	}
+	r, _ := lookup(cfg.Provider)
+	llm, err := r.factory(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if pseudonyms != nil {
+		llm = WithPseudonyms(llm, pseudonyms)
+	}
+	// Redact before pseudonymizing, since replacing host names breaks up
+	// emails and other secrets the redactor recognizes
+	if cfg.Redactor != NoRedaction {
+		llm = WithRedaction(llm, cfg.Redactor)
+	}
+	if cfg.CacheTTL == 0 {
+		return llm, nil
+	}
+	if cfg.CacheDir != "" {
+		return WithCache(llm, NewDiskCache(cfg.CacheDir, cfg.CacheTTL), cfg), nil
+	}
+	return WithCache(llm, NewMemoryCache(cfg.CacheTTL), cfg), nil
+}
+
+// apiKey returns cfg.APIKey, falling back to the given environment variable
+func apiKey(cfg Config, env string) string {
+	if cfg.APIKey != "" {
+		return cfg.APIKey
+	}
+	return os.Getenv(env)
+}
+
+// ErrNoClusterAccess reports a provider asked to diagnose a run it cannot
+// read
+var ErrNoClusterAccess = errors.New("cannot read the cluster")
+
+// clusterless are the built-in providers that only see the text sent to
+// them. Lightspeed reads the run by name itself.
+var clusterless = map[string]bool{
+	ProviderGemini: true, ProviderAnthropic: true, ProviderAzureOpenAI: true, ProviderOpenAICompatible: true, ProviderRules: true,
+}
+
+// CheckClusterAccess returns an error wrapping ErrNoClusterAccess when
+// provider cannot diagnose a run from its name and namespace alone
+func CheckClusterAccess(provider string) error {
+	if !clusterless[provider] {
+		return nil
+	}
+	return fmt.Errorf("%s %w: diagnosing a run by name needs --provider %s; "+
+		"for %s, pipe the step logs to explain instead, e.g. `tkn taskrun logs <name> | tkn-assist explain --stdin --provider %s`",
+		provider, ErrNoClusterAccess, ProviderLightspeed, provider, provider)
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNew_SystemPromptIsProtected(t *testing.T) {
+	var req chatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ask host-A"}}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	llm, err := New(Config{
+		Provider:     ProviderOpenAICompatible,
+		BaseURL:      srv.URL,
+		Model:        "m",
+		SafeMode:     true,
+		SystemPrompt: "You debug Tekton runs on api.prod.example.com. Escalate to jane.doe@example.com.",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := llm.Analyze(context.Background(), "Why did it fail on api.prod.example.com?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	system := req.Messages[0].Content
+	for _, leak := range []string{"api.prod.example.com", "jane.doe"} {
+		if strings.Contains(system, leak) || strings.Contains(req.Messages[1].Content, leak) {
+			t.Errorf("%q was sent to the provider: %+v", leak, req.Messages)
+		}
+	}
+	if !strings.Contains(req.Messages[1].Content, "host-A") || !strings.Contains(resp, "api.prod.example.com") {
+		t.Errorf("expected the query to share the system prompt tokens, got %+v and %q", req.Messages, resp)
+	}
+}
+
+func TestCheckClusterAccess(t *testing.T) {
+	tests := []struct {
+		provider string
+		rejected bool
+	}{
+		{"", false},
+		{ProviderLightspeed, false},
+		{"custom", false},
+		{ProviderGemini, true},
+		{ProviderAnthropic, true},
+		{ProviderAzureOpenAI, true},
+		{ProviderOpenAICompatible, true},
+		{ProviderRules, true},
+	}
+	for _, tt := range tests {
+		err := CheckClusterAccess(tt.provider)
+		if errors.Is(err, ErrNoClusterAccess) != tt.rejected {
+			t.Errorf("%q: unexpected error %v", tt.provider, err)
+		}
+		if tt.rejected && !strings.Contains(err.Error(), "explain --stdin --provider "+tt.provider) {
+			t.Errorf("%q: expected the error to point to explain, got %v", tt.provider, err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/types"
+)
+
+// MetadataKey is the top-level response field holding Metadata
+const MetadataKey = "metadata"
+
+// StructuredKey is the top-level response field holding the validated
+// structured analysis
+const StructuredKey = "structured"
+
+// Metadata records how a diagnosis was produced so quality regressions can
+// be traced to a specific provider, model or prompt template.
+type Metadata struct {
+	Provider      string `json:"provider"`
+	Model         string `json:"model,omitempty"`
+	PromptVersion string `json:"prompt_version"`
+	PromptHash    string `json:"prompt_hash"`
+	DurationMS    int64  `json:"duration_ms"`
+	Usage         *Usage `json:"usage,omitempty"`
+}
+
+// Result is a provider response together with its Metadata
+type Result struct {
+	Response string
+	Metadata Metadata
+	// Structured is the validated structured analysis, or nil when the
+	// model answered in prose
+	Structured *types.Analysis
+	// Confidence is set by Assess
+	Confidence *Confidence
+	// Debug is embedded under PromptDebugKey when set
+	Debug *PromptDebug
+	// Snippets checks the YAML snippets of the answer
+	Snippets []SnippetCheck
+}
+
+// Run sends the query through llm and records metadata about the call.
+// Failures of the provider itself are returned as *SelfDiagnosisError when
+// a remediation is known.
+func Run(ctx context.Context, llm LLM, query string) (*Result, error) {
+	start := time.Now()
+	resp, err := llm.Analyze(ctx, query)
+	if err != nil {
+		return nil, withRemediation(llm.Name(), err)
+	}
+	res := &Result{
+		Response:   resp,
+		Structured: structured(resp),
+		Metadata: Metadata{
+			Provider:      llm.Name(),
+			Model:         llm.Model(),
+			PromptVersion: PromptVersion,
+			PromptHash:    PromptHash(query),
+			DurationMS:    time.Since(start).Milliseconds(),
+			Usage:         responseUsage(resp),
+		},
+	}
+	res.Assess("")
+	res.checkSnippets()
+	return res, nil
+}
+
+// JSON returns the response with Metadata embedded under MetadataKey, and
+// Structured, Confidence and Debug under StructuredKey, ConfidenceKey and
+// PromptDebugKey when set. The response is returned untouched
+// when it is not a JSON object or already carries a metadata field.
+func (r *Result) JSON() string {
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(r.Response), &obj); err != nil || obj == nil {
+		return r.Response
+	}
+	if _, exists := obj[MetadataKey]; exists {
+		return r.Response
+	}
+	obj[MetadataKey] = r.Metadata
+	if r.Structured != nil {
+		obj[StructuredKey] = r.Structured
+	}
+	if r.Confidence != nil {
+		obj[ConfidenceKey] = r.Confidence
+	}
+	if r.Debug != nil {
+		obj[PromptDebugKey] = r.Debug
+	}
+	if len(r.Snippets) > 0 {
+		obj[SnippetsKey] = r.Snippets
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return r.Response
+	}
+	return string(b)
+}
+
+// structured returns the validated structured analysis of resp, or nil
+func structured(resp string) *types.Analysis {
+	a, err := ParseStructured(resp)
+	if err != nil {
+		return nil
+	}
+	return a
+}
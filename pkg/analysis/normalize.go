@@ -0,0 +1,76 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minBlobLen is the shortest base64 run that gets folded. Shorter runs are
+// usually digests or IDs that are worth keeping.
+const minBlobLen = 256
+
+// base64Blob matches long runs of base64 (standard or URL alphabet)
+var base64Blob = regexp.MustCompile(`[A-Za-z0-9+/_-]{` + fmt.Sprint(minBlobLen) + `,}={0,2}`)
+
+// NormalizeLog folds content that wastes prompt budget without helping the
+// diagnosis: long base64 payloads become "[1.2KB base64 blob omitted]" and
+// lines that are mostly binary become "[binary data omitted: N bytes]".
+// It runs before snippet extraction.
+func NormalizeLog(log string) string {
+	lines := strings.Split(log, "\n")
+	for i, line := range lines {
+		if isBinary(line) {
+			lines[i] = fmt.Sprintf("[binary data omitted: %d bytes]", len(line))
+			continue
+		}
+		lines[i] = base64Blob.ReplaceAllStringFunc(line, func(blob string) string {
+			return fmt.Sprintf("[%s base64 blob omitted]", humanSize(len(blob)))
+		})
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isBinary reports whether more than 30% of the line is invalid UTF-8 or
+// non-printable control characters
+func isBinary(line string) bool {
+	if len(line) < 16 {
+		return false
+	}
+	bad := 0
+	for i := 0; i < len(line); {
+		r, size := utf8.DecodeRuneInString(line[i:])
+		if (r == utf8.RuneError && size == 1) || (!unicode.IsPrint(r) && !unicode.IsSpace(r)) {
+			bad++
+		}
+		i += size
+	}
+	return bad*10 > len(line)*3
+}
+
+func humanSize(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", n)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// chatMessage is a message of the OpenAI chat completions API, which is
+// also spoken by Azure OpenAI and most self-hosted inference servers
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+	// ReasoningContent is the reasoning returned separately by some
+	// inference servers, e.g. vLLM with a reasoning parser
+	ReasoningContent string `json:"reasoning_content,omitempty"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model,omitempty"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	TopP        *float64      `json:"top_p,omitempty"`
+	Stop        []string      `json:"stop,omitempty"`
+	Stream      bool          `json:"stream,omitempty"`
+
+	MaxCompletionTokens int    `json:"max_completion_tokens,omitempty"`
+	ReasoningEffort     string `json:"reasoning_effort,omitempty"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message      chatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		Details          struct {
+			ReasoningTokens int `json:"reasoning_tokens"`
+		} `json:"completion_tokens_details"`
+	} `json:"usage"`
+}
+
+// ErrReasoningExhausted reports a reasoning model that reached its output
+// limit before writing an answer
+var ErrReasoningExhausted = errors.New("spent the whole output token limit on reasoning and returned no answer")
+
+// newChatRequest builds a single-turn chat request
+func newChatRequest(model, systemPrompt, query string, s sampling) chatRequest {
+	var messages []chatMessage
+	if systemPrompt != "" {
+		messages = append(messages, chatMessage{Role: "system", Content: systemPrompt})
+	}
+	messages = append(messages, chatMessage{Role: "user", Content: query})
+	return chatRequest{
+		Model:       model,
+		Messages:    messages,
+		MaxTokens:   s.maxTokens,
+		Temperature: s.temperature,
+		TopP:        s.topP,
+		Stop:        s.stop,
+
+		MaxCompletionTokens: s.maxCompletionTokens,
+		ReasoningEffort:     s.reasoningEffort,
+	}
+}
+
+// lightspeedShape converts the first choice into the Lightspeed response
+// shape. A reasoning model that only returned reasoning is answered from
+// it; reasoning cut off at the output limit is never used as the answer.
+func (r *chatResponse) lightspeedShape(provider string) (string, error) {
+	if len(r.Choices) == 0 {
+		return "", fmt.Errorf("%s returned no choices", provider)
+	}
+	choice := r.Choices[0]
+	text := strings.TrimSpace(choice.Message.Content)
+	if text == "" && choice.FinishReason == "length" {
+		return "", reasoningExhausted(provider, r.Usage.Details.ReasoningTokens)
+	}
+	if text == "" {
+		text = strings.TrimSpace(choice.Message.ReasoningContent)
+	}
+	if text == "" {
+		return "", fmt.Errorf("%s returned an empty answer (finish reason %s)", provider, choice.FinishReason)
+	}
+	return lightspeedShape(text, r.Usage.PromptTokens, r.Usage.CompletionTokens)
+}
+
+// reasoningExhausted returns ErrReasoningExhausted for provider, with the
+// reasoning token count when the provider reported it
+func reasoningExhausted(provider string, tokens int) error {
+	if tokens > 0 {
+		return fmt.Errorf("%s %w (%d reasoning tokens)", provider, ErrReasoningExhausted, tokens)
+	}
+	return fmt.Errorf("%s %w", provider, ErrReasoningExhausted)
+}
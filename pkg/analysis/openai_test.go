@@ -0,0 +1,117 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNew_ReasoningModels(t *testing.T) {
+	var got map[string]interface{}
+	answer := `{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(answer))
+	}))
+	t.Cleanup(srv.Close)
+
+	llm, err := New(Config{
+		Provider:            ProviderOpenAICompatible,
+		Model:               "o4-mini",
+		BaseURL:             srv.URL,
+		ReasoningEffort:     "low",
+		MaxCompletionTokens: 4096,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := llm.Analyze(context.Background(), "q"); err != nil {
+		t.Fatal(err)
+	}
+	if got["reasoning_effort"] != "low" || got["max_completion_tokens"] != 4096.0 || got["max_tokens"] != nil {
+		t.Fatalf("reasoning parameters not sent: %v", got)
+	}
+
+	// Reasoning-only answers are used as the answer
+	answer = `{"choices":[{"message":{"role":"assistant","content":"","reasoning_content":"the registry is down"},"finish_reason":"stop"}]}`
+	resp, err := llm.Analyze(context.Background(), "q2")
+	if err != nil || !strings.Contains(resp, "the registry is down") {
+		t.Fatalf("expected the reasoning as answer, got %q (%v)", resp, err)
+	}
+
+	// Running out of tokens while reasoning explains how to fix it
+	answer = `{"choices":[{"message":{"role":"assistant","content":""},"finish_reason":"length"}],"usage":{"completion_tokens":4096,"completion_tokens_details":{"reasoning_tokens":4096}}}`
+	_, err = Run(context.Background(), llm, "q3")
+	if !errors.Is(err, ErrReasoningExhausted) || !strings.Contains(err.Error(), "--max-completion-tokens") {
+		t.Fatalf("expected a reasoning exhausted error with remediation, got %v", err)
+	}
+
+	for _, cfg := range []Config{
+		{Provider: ProviderOpenAICompatible, BaseURL: srv.URL, ReasoningEffort: "extreme"},
+		{Provider: ProviderOpenAICompatible, BaseURL: srv.URL, MaxTokens: 256, MaxCompletionTokens: 4096},
+		{Provider: ProviderAnthropic, APIKey: "k", ReasoningEffort: "low"},
+		{Provider: ProviderOpenAICompatible, BaseURL: srv.URL, Model: "o4-mini", ReasoningEffort: "low", TopP: new(float64)},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Fatalf("expected %+v to be rejected", cfg)
+		}
+	}
+}
+
+func TestChatResponse_LightspeedShape(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		want      string
+		exhausted bool
+	}{
+		{"answer", `{"choices":[{"message":{"content":"disk full"},"finish_reason":"stop"}]}`, "disk full", false},
+		{"reasoning only", `{"choices":[{"message":{"content":"","reasoning_content":"the registry is down"},"finish_reason":"stop"}]}`, "the registry is down", false},
+		{"reasoning cut off", `{"choices":[{"message":{"content":"","reasoning_content":"first check the"},"finish_reason":"length"}]}`, "", true},
+		{"hidden reasoning cut off", `{"choices":[{"message":{"content":""},"finish_reason":"length"}],"usage":{"completion_tokens_details":{"reasoning_tokens":4096}}}`, "", true},
+	}
+	for _, tt := range tests {
+		var r chatResponse
+		if err := json.Unmarshal([]byte(tt.body), &r); err != nil {
+			t.Fatal(err)
+		}
+		got, err := r.lightspeedShape(ProviderOpenAICompatible)
+		if errors.Is(err, ErrReasoningExhausted) != tt.exhausted || !strings.Contains(got, tt.want) {
+			t.Errorf("%s: got %q, %v", tt.name, got, err)
+		}
+	}
+}
+
+func TestStreamChatSSE_ReasoningExhausted(t *testing.T) {
+	body := io.NopCloser(strings.NewReader("data: {\"choices\":[{\"delta\":{\"reasoning_content\":\"first\"}}]}\n\n" +
+		"data: {\"choices\":[{\"delta\":{},\"finish_reason\":\"length\"}]}\n\ndata: [DONE]\n\n"))
+	var err error
+	for c := range streamChatSSE(context.Background(), ProviderOpenAICompatible, body) {
+		if c.Text != "" {
+			t.Fatalf("expected no answer, got %q", c.Text)
+		}
+		err = c.Err
+	}
+	if !errors.Is(err, ErrReasoningExhausted) {
+		t.Fatalf("expected a reasoning exhausted error, got %v", err)
+	}
+}
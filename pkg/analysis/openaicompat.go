@@ -0,0 +1,145 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	// ProviderOpenAICompatible selects a self-hosted server speaking the
+	// OpenAI chat completions API, such as vLLM or Hugging Face TGI
+	ProviderOpenAICompatible = "openai-compatible"
+
+	// DefaultCompletionsPath is appended to the base URL unless
+	// Config.CompletionsPath is set
+	DefaultCompletionsPath = "/v1/chat/completions"
+
+	// OpenAIAPIKeyEnv is read when Config.APIKey is empty. The key is
+	// optional since many self-hosted servers run without authentication.
+	OpenAIAPIKeyEnv = "OPENAI_API_KEY"
+	// OpenAIBaseURLEnv is read when Config.BaseURL is empty
+	OpenAIBaseURLEnv = "OPENAI_BASE_URL"
+	// OpenAIExtraHeadersEnv holds comma separated Name=Value headers sent
+	// in addition to Config.Headers
+	OpenAIExtraHeadersEnv = "OPENAI_EXTRA_HEADERS"
+)
+
+func init() {
+	Register(ProviderOpenAICompatible, func(cfg Config) (LLM, error) {
+		return NewOpenAICompatibleLLM(cfg), nil
+	})
+	RegisterValidator(ProviderOpenAICompatible, func(cfg Config) []error {
+		var errs []error
+		if openAICompatibleURL(cfg) == "" {
+			errs = append(errs, &ConfigError{Field: "base URL", Message: "openai-compatible requires the server URL (set " + OpenAIBaseURLEnv + ")"})
+		}
+		if cfg.Model == "" {
+			errs = append(errs, &ConfigError{Field: "model", Message: "openai-compatible requires the served model name"})
+		}
+		return errs
+	})
+}
+
+// OpenAICompatibleLLM talks to an OpenAI-compatible chat completions endpoint
+type OpenAICompatibleLLM struct {
+	endpoint     string
+	model        string
+	headers      map[string]string
+	sampling     sampling
+	systemPrompt string
+	client       *http.Client
+}
+
+// NewOpenAICompatibleLLM creates an LLM for an OpenAI-compatible server
+func NewOpenAICompatibleLLM(cfg Config) *OpenAICompatibleLLM {
+	path := cfg.CompletionsPath
+	if path == "" {
+		path = DefaultCompletionsPath
+	}
+
+	headers := envHeaders(os.Getenv(OpenAIExtraHeadersEnv))
+	if key := apiKey(cfg, OpenAIAPIKeyEnv); key != "" {
+		headers["Authorization"] = "Bearer " + key
+	}
+
+	return &OpenAICompatibleLLM{
+		endpoint:     joinURL(openAICompatibleURL(cfg), path),
+		model:        cfg.Model,
+		headers:      headers,
+		sampling:     newSampling(cfg),
+		systemPrompt: cfg.SystemPrompt,
+		client:       newHTTPClient(cfg),
+	}
+}
+
+// Analyze sends the query as a single chat turn and returns the answer in
+// the Lightspeed response shape
+func (o *OpenAICompatibleLLM) Analyze(ctx context.Context, query string) (string, error) {
+	req := newChatRequest(o.model, o.systemPrompt, query, o.sampling)
+
+	var resp chatResponse
+	if err := postJSON(ctx, o.client, ProviderOpenAICompatible, o.endpoint, o.headers, req, &resp); err != nil {
+		return "", err
+	}
+	return resp.lightspeedShape(ProviderOpenAICompatible)
+}
+
+// AnalyzeStream implements Streamer
+func (o *OpenAICompatibleLLM) AnalyzeStream(ctx context.Context, query string) (<-chan StreamChunk, error) {
+	req := newChatRequest(o.model, o.systemPrompt, query, o.sampling)
+	req.Stream = true
+
+	body, err := postStream(ctx, o.client, ProviderOpenAICompatible, o.endpoint, o.headers, req)
+	if err != nil {
+		return nil, err
+	}
+	return streamChatSSE(ctx, ProviderOpenAICompatible, body), nil
+}
+
+// Name implements LLM
+func (o *OpenAICompatibleLLM) Name() string {
+	return ProviderOpenAICompatible
+}
+
+// Model implements LLM
+func (o *OpenAICompatibleLLM) Model() string {
+	return o.model
+}
+
+func openAICompatibleURL(cfg Config) string {
+	if cfg.BaseURL != "" {
+		return cfg.BaseURL
+	}
+	return os.Getenv(OpenAIBaseURLEnv)
+}
+
+// envHeaders parses comma separated Name=Value pairs, skipping malformed
+// entries
+func envHeaders(s string) map[string]string {
+	headers := map[string]string{}
+	for _, kv := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(kv, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			continue
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+	return headers
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/types"
+	"gopkg.in/yaml.v2"
+)
+
+// PatternsEnv names the default pattern catalog file
+const PatternsEnv = "TKN_ASSIST_PATTERNS"
+
+// patternCatalog is the YAML layout of a pattern catalog:
+//
+//	patterns:
+//	- name: artifactory-forbidden
+//	  pattern: 'artifactory\.example\.com.*(403|Forbidden)'
+//	  category: auth
+//	  root_cause: The CI token was rejected by Artifactory.
+//	  solutions:
+//	  - Request a new token from the infra team
+type patternCatalog struct {
+	Patterns []struct {
+		Name      string   `yaml:"name"`
+		Pattern   string   `yaml:"pattern"`
+		Reason    string   `yaml:"reason"`
+		RootCause string   `yaml:"root_cause"`
+		Analysis  string   `yaml:"analysis"`
+		Category  string   `yaml:"category"`
+		Solutions []string `yaml:"solutions"`
+	} `yaml:"patterns"`
+}
+
+// LoadPatterns parses a YAML pattern catalog into rules. Patterns are Go
+// regular expressions matched against the log; texts may use templates over
+// a RuleMatch.
+func LoadPatterns(data []byte) ([]Rule, error) {
+	var catalog patternCatalog
+	if err := yaml.UnmarshalStrict(data, &catalog); err != nil {
+		return nil, fmt.Errorf("invalid pattern catalog: %w", err)
+	}
+
+	var rules []Rule
+	var errs []error
+	for i, p := range catalog.Patterns {
+		name := p.Name
+		if name == "" {
+			name = fmt.Sprintf("pattern %d", i+1)
+		}
+		re, err := regexp.Compile(p.Pattern)
+		switch {
+		case p.Pattern == "":
+			errs = append(errs, fmt.Errorf("%s: pattern is empty", name))
+			continue
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		category := types.Category(strings.ToLower(p.Category))
+		if category == "" {
+			category = types.CategoryUnknown
+		}
+		rule := Rule{
+			Name:      name,
+			Pattern:   re,
+			Reason:    p.Reason,
+			RootCause: p.RootCause,
+			Analysis:  p.Analysis,
+			Category:  category,
+			Solutions: p.Solutions,
+		}
+		if p.Reason != "" {
+			rule.reason = compileReason(p.Reason)
+		}
+		if err := (&types.Analysis{RootCause: rule.RootCause, Category: category, Solutions: rule.Solutions}).Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		for _, text := range append([]string{rule.RootCause, rule.Analysis}, rule.Solutions...) {
+			if _, err := template.New(name).Parse(text); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+		rules = append(rules, rule)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid pattern catalog: %w", err)
+	}
+	return rules, nil
+}
+
+// WithPattern adds the diagnosis of a matched catalog pattern to a query,
+// so the model builds on the organization's known remediation
+func WithPattern(query string, m *RuleMatch) string {
+	if m == nil {
+		return query
+	}
+	var b strings.Builder
+	b.WriteString(query)
+	b.WriteString("\n\nA known-failure pattern maintained by the platform team (" + m.Rule.Name + ") matched the line: " + m.Line)
+	b.WriteString("\nIts diagnosis: " + m.expand(m.Rule.RootCause))
+	for _, s := range m.Rule.Solutions {
+		b.WriteString("\n- " + m.expand(s))
+	}
+	b.WriteString("\nInclude this remediation in your solutions unless the log clearly contradicts it.")
+	return b.String()
+}
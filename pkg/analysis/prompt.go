@@ -0,0 +1,161 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/types"
+)
+
+// PromptVersion identifies the query templates of this package. Bump it
+// whenever their wording changes so diagnoses can be traced back to a
+// template revision; TestPromptVersion fails until it is bumped.
+const PromptVersion = "v3"
+
+// responseShape asks for solutions and a JSON shape the CLI knows how to
+// render and ParseStructured can validate
+var responseShape = "Provide a brief summary, a clear root-cause analysis, and 3-5 actionable solutions. " +
+	"If possible, respond as a JSON object with fields: response (string), analysis (string), " +
+	"root_cause (one sentence), category (one of " + categoryList() + "), solutions (array of strings), " +
+	"confidence (number from 0 to 1, how sure you are of the root cause)."
+
+func categoryList() string {
+	names := make([]string, len(types.Categories))
+	for i, c := range types.Categories {
+		names[i] = string(c)
+	}
+	return strings.Join(names, ", ")
+}
+
+// TaskRunQuery builds the chat-style query for a failed TaskRun
+func TaskRunQuery(name, namespace string) string {
+	return fmt.Sprintf("Why is my Tekton TaskRun '%s' failing in namespace '%s'? ", name, namespace) + responseShape
+}
+
+// PipelineRunQuery builds the chat-style query for a failed PipelineRun
+func PipelineRunQuery(name, namespace string) string {
+	return fmt.Sprintf("Why is my Tekton PipelineRun '%s' failing in namespace '%s'? ", name, namespace) + responseShape
+}
+
+// StuckPipelineRunQuery builds the query for a PipelineRun that has not
+// failed but has been Pending or queued for longer than expected
+func StuckPipelineRunQuery(name, namespace string) string {
+	return fmt.Sprintf("My Tekton PipelineRun '%s' in namespace '%s' has not failed but is stuck in a Pending or queued state. ", name, namespace) +
+		"Check whether spec.status is set to PipelineRunPending, whether the Tekton admission webhooks are unavailable, " +
+		"and whether ResourceQuota or LimitRange objects are preventing TaskRun pods from being created. " +
+		responseShape
+}
+
+// LogQuery builds the query explaining an arbitrary log excerpt that was not
+// read from a cluster resource (e.g. copied from another CI system)
+func LogQuery(snippet string) string {
+	return "The following log was produced by a failed CI/CD build step. " +
+		"Explain why it failed. " + responseShape +
+		"\n\nLog:\n```\n" + snippet + "\n```"
+}
+
+// WithHint appends user supplied context (e.g. "we upgraded the base image
+// yesterday") to a query. An empty hint leaves the query unchanged.
+func WithHint(query, hint string) string {
+	hint = strings.TrimSpace(hint)
+	if hint == "" {
+		return query
+	}
+	return query + "\n\nAdditional context provided by the user (treat as a lead, not as fact): " + hint
+}
+
+// Audience tailors the depth of an explanation to the reader
+type Audience string
+
+const (
+	AudienceBeginner Audience = "beginner"
+	AudienceStandard Audience = "standard"
+	AudienceExpert   Audience = "expert"
+)
+
+// ParseAudience validates s; an empty string selects AudienceStandard
+func ParseAudience(s string) (Audience, error) {
+	switch a := Audience(strings.ToLower(strings.TrimSpace(s))); a {
+	case "":
+		return AudienceStandard, nil
+	case AudienceBeginner, AudienceStandard, AudienceExpert:
+		return a, nil
+	}
+	return "", fmt.Errorf("unknown audience %q (expected beginner, standard or expert)", s)
+}
+
+// audienceGuidance holds the instructions added for each non-standard
+// audience; the standard audience keeps the query unchanged
+var audienceGuidance = map[Audience]string{
+	AudienceBeginner: "The reader is new to Tekton and Kubernetes. Briefly explain the Tekton concepts involved " +
+		"(for example Task, Step, PipelineRun, workspace or service account) in plain language, " +
+		"and describe each solution as concrete steps.",
+	AudienceExpert: "The reader is an experienced Tekton operator. Be terse: state the root cause in one sentence, " +
+		"skip background explanations, and give exact commands (oc, kubectl or tkn) or YAML changes as solutions.",
+}
+
+// WithAudience adapts a query to the audience
+func WithAudience(query string, audience Audience) string {
+	guidance, ok := audienceGuidance[audience]
+	if !ok {
+		return query
+	}
+	return query + "\n\n" + guidance
+}
+
+// WithLanguage asks for the answer in language, an English language name
+// such as "Japanese". An empty language leaves the query unchanged.
+func WithLanguage(query, language string) string {
+	if language == "" {
+		return query
+	}
+	return query + "\n\nWrite the summary, analysis, root cause and solutions in " + language + ". " +
+		"Keep JSON field names, category values, commands, YAML, and Tekton or Kubernetes field names in English."
+}
+
+// Turn is one question/answer exchange of a chat session
+type Turn struct {
+	Question string
+	Answer   string
+}
+
+// MaxChatTurns bounds how much history is replayed on each chat question
+const MaxChatTurns = 10
+
+// ChatQuery builds a follow-up question that replays the most recent turns
+// of the conversation, so providers without server-side sessions keep the
+// context of the run being discussed.
+func ChatQuery(history []Turn, question string) string {
+	if len(history) > MaxChatTurns {
+		history = history[len(history)-MaxChatTurns:]
+	}
+	var b strings.Builder
+	b.WriteString("We are discussing a failed Tekton run. Conversation so far:\n")
+	for _, t := range history {
+		b.WriteString("\nUser: " + t.Question + "\nAssistant: " + t.Answer + "\n")
+	}
+	b.WriteString("\nAnswer the next question in plain text, concisely.\nUser: " + question)
+	return b.String()
+}
+
+// PromptHash returns a short stable fingerprint of the final query text
+func PromptHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])[:12]
+}
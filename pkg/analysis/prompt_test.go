@@ -0,0 +1,53 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+// promptHashes records the hash of the rendered templates of each
+// PromptVersion
+var promptHashes = map[string]string{
+	"v3": "5c74574abf37",
+}
+
+// TestPromptVersion fails when the wording of a query template changes
+// without bumping PromptVersion
+func TestPromptVersion(t *testing.T) {
+	rule := &RuleMatch{
+		Rule: &Rule{Name: "rule", RootCause: "root cause", Solutions: []string{"solution"}},
+		Line: "line",
+	}
+	templates := []string{
+		TaskRunQuery("run", "ns"),
+		PipelineRunQuery("run", "ns"),
+		StuckPipelineRunQuery("run", "ns"),
+		LogQuery("log"),
+		WithHint("query", "hint"),
+		WithAudience("query", AudienceBeginner),
+		WithAudience("query", AudienceExpert),
+		WithLanguage("query", "Japanese"),
+		ChatQuery([]Turn{{Question: "question", Answer: "answer"}}, "next"),
+		GroupedQuery([]RunID{{Kind: "TaskRun", Namespace: "ns", Name: "run"}}),
+		WithRunbooks("query", []RunbookSection{{Title: "title", Text: "text"}}),
+		WithPattern("query", rule),
+	}
+	got := PromptHash(strings.Join(templates, "\n"))
+	if want := promptHashes[PromptVersion]; got != want {
+		t.Fatalf("the query templates of %s changed (hash %s, want %s): bump PromptVersion and record the new hash", PromptVersion, got, want)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PromptDebugKey is the top-level response field holding PromptDebug
+const PromptDebugKey = "prompt_debug"
+
+// Reasons a log line is left out of, or shortened in, the prompt
+const (
+	ExcludedRedacted    = "redacted"
+	ExcludedNormalized  = "normalized"
+	ExcludedLineLimit   = "line_limit"
+	ExcludedTokenBudget = "token_budget"
+)
+
+// LogPrompt describes how a log excerpt is turned into a query
+type LogPrompt struct {
+	// MaxLines and MaxTokens bound the snippet; zero selects the defaults
+	MaxLines  int
+	MaxTokens int
+	// Runbooks adds matching sections of the bundled runbooks
+	Runbooks bool
+	Audience Audience
+	// Language is the English name of the answer language; empty means
+	// English
+	Language string
+	Hint     string
+	// Patterns are matched against the whole log; the first match is
+	// added to the query with WithPattern
+	Patterns []Rule
+	// Redactor masks the log and hint; nil applies the built-in rules
+	Redactor *Redactor
+}
+
+// PromptDebug reports which evidence a query includes and why the rest of
+// the log was left out, so a missed diagnosis can be traced to the budget
+type PromptDebug struct {
+	LogLines      int         `json:"log_lines"`
+	IncludedLines int         `json:"included_lines"`
+	Excluded      []Exclusion `json:"excluded,omitempty"`
+	LogTokens     int         `json:"log_tokens"`
+	TokenBudget   int         `json:"token_budget"`
+	Runbooks      []string    `json:"runbooks,omitempty"`
+	Pattern       string      `json:"pattern,omitempty"`
+	Audience      Audience    `json:"audience"`
+	Language      string      `json:"language,omitempty"`
+	Hint          bool        `json:"hint"`
+	PromptTokens  int         `json:"prompt_tokens"`
+}
+
+// Exclusion counts the log lines dropped or shortened for one reason
+type Exclusion struct {
+	Reason string `json:"reason"`
+	Lines  int    `json:"lines"`
+	Detail string `json:"detail"`
+}
+
+// Build returns the query explaining log and a report of how it was
+// assembled
+func (p LogPrompt) Build(log string) (string, PromptDebug) {
+	maxLines, budget := p.MaxLines, p.MaxTokens
+	if maxLines <= 0 {
+		maxLines = DefaultSnippetLines
+	}
+	if budget <= 0 {
+		budget = DefaultSnippetTokens
+	}
+	audience := p.Audience
+	if audience == "" {
+		audience = AudienceStandard
+	}
+	debug := PromptDebug{
+		LogLines:    countLines(log),
+		TokenBudget: budget,
+		Audience:    audience,
+		Language:    p.Language,
+		Hint:        strings.TrimSpace(p.Hint) != "",
+	}
+
+	redacted, n := p.Redactor.Redact(log)
+	if n > 0 {
+		debug.exclude(ExcludedRedacted, changedLines(log, redacted), fmt.Sprintf("%d credentials or personal data values masked with %s markers", n, redactedPrefix+"]"))
+	}
+	log = redacted
+	hint, _ := p.Redactor.Redact(p.Hint)
+
+	normalized := NormalizeLog(log)
+	if n := changedLines(log, normalized); n > 0 {
+		debug.exclude(ExcludedNormalized, n, "base64 blobs or binary data folded into a size marker")
+	}
+
+	extracted := ExtractSnippet(normalized, maxLines)
+	if n := debug.LogLines - countLines(extracted); n > 0 {
+		debug.exclude(ExcludedLineLimit, n, fmt.Sprintf("only error lines, their context and the tail fit in %d lines", maxLines))
+	}
+
+	snippet := FitTokens(extracted, budget)
+	if snippet != extracted {
+		// FitTokens prefixes an omission marker to the lines it keeps
+		kept := countLines(snippet) - 1
+		debug.exclude(ExcludedTokenBudget, countLines(extracted)-kept, fmt.Sprintf("earlier lines dropped to fit %d estimated tokens", budget))
+		debug.IncludedLines = kept
+	} else {
+		debug.IncludedLines = countLines(snippet)
+	}
+	debug.LogTokens = EstimateTokens(snippet)
+
+	query := LogQuery(snippet)
+	if p.Runbooks {
+		sections := RetrieveRunbooks(snippet, DefaultRunbookSections)
+		for _, s := range sections {
+			debug.Runbooks = append(debug.Runbooks, s.Source+": "+s.Title)
+		}
+		query = WithRunbooks(query, sections)
+	}
+	if m, ok := Match(p.Patterns, normalized); ok {
+		debug.Pattern = m.Rule.Name
+		query = WithPattern(query, m)
+	}
+	query = WithHint(WithLanguage(WithAudience(query, audience), p.Language), hint)
+	debug.PromptTokens = EstimateTokens(query)
+	return query, debug
+}
+
+func (d *PromptDebug) exclude(reason string, lines int, detail string) {
+	d.Excluded = append(d.Excluded, Exclusion{Reason: reason, Lines: lines, Detail: detail})
+}
+
+func countLines(text string) int {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return 0
+	}
+	return strings.Count(text, "\n") + 1
+}
+
+// changedLines counts the lines that differ between two texts with the
+// same number of lines
+func changedLines(before, after string) int {
+	a, b := strings.Split(before, "\n"), strings.Split(after, "\n")
+	n := 0
+	for i := range a {
+		if i < len(b) && a[i] != b[i] {
+			n++
+		}
+	}
+	return n
+}
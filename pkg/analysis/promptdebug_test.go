@@ -0,0 +1,44 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogPrompt_Debug(t *testing.T) {
+	var log strings.Builder
+	for i := 0; i < 500; i++ {
+		log.WriteString("downloading layer\n")
+	}
+	log.WriteString("Error: image not found\n")
+
+	query, debug := LogPrompt{MaxLines: 20, Runbooks: true}.Build(log.String())
+	if debug.LogLines != 501 || debug.IncludedLines != 5 || debug.PromptTokens == 0 {
+		t.Fatalf("unexpected prompt debug: %+v", debug)
+	}
+	if len(debug.Excluded) != 1 || debug.Excluded[0].Reason != ExcludedLineLimit || debug.Excluded[0].Lines != 496 {
+		t.Fatalf("expected 496 lines excluded by the line limit, got %+v", debug.Excluded)
+	}
+	if !strings.Contains(query, "Error: image not found") {
+		t.Fatalf("expected the error line in the query, got %q", query)
+	}
+
+	res := &Result{Response: `{"response":"ok"}`, Debug: &debug}
+	if !strings.Contains(res.JSON(), `"prompt_debug":{"log_lines":501`) {
+		t.Fatalf("expected prompt_debug in the JSON response, got %s", res.JSON())
+	}
+}
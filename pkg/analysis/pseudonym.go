@@ -0,0 +1,275 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SafeModeEnv enables safe mode by default when set to a true value
+const SafeModeEnv = "TKN_ASSIST_SAFE_MODE"
+
+var (
+	// runName and namespaceName find identifiers by the text around them,
+	// e.g. "TaskRun 'build-x7k2'", "pipelineruns/release" or
+	// "namespace: team-a"
+	runName       = regexp.MustCompile(`(?i)\b(?:taskrun|pipelinerun)s?(?:\.tekton\.dev)?(?:/|\s+["'])([a-z0-9][a-z0-9.-]*[a-z0-9])`)
+	namespaceName = regexp.MustCompile(`(?i)\bnamespaces?(?:/|:\s*["']?|\s+["'])([a-z0-9][a-z0-9-]*[a-z0-9])`)
+	// imageRef matches image references with a registry or repository
+	// path and a tag or digest
+	imageRef = regexp.MustCompile(`\b(?:[a-z0-9](?:[a-z0-9.-]*[a-z0-9])?(?::\d+)?/)+[a-z0-9](?:[a-z0-9._-]*[a-z0-9])?(?::\w[\w.-]{0,127}|@sha256:[a-f0-9]{64})`)
+	// hostName matches host names with a common top-level domain; urlHost
+	// and portHost find other dotted names by their use in a URL, after an
+	// @ or before a port, and ipv4 matches addresses
+	hostName = regexp.MustCompile(`(?i)\b(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+(?:com|net|org|io|dev|cloud|local|internal|lan|corp|intra|svc)\b`)
+	urlHost  = regexp.MustCompile(`(?i)(?:://|@)((?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z][a-z0-9-]*)\b`)
+	portHost = regexp.MustCompile(`(?i)\b((?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z][a-z0-9-]*):\d{2,5}\b`)
+	ipv4     = regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`)
+	// pseudonym matches the tokens handed out by a Pseudonymizer
+	pseudonym = regexp.MustCompile(`\b(?:ns|run)-\d+\b|\b(?:image|host)-[A-Z]+\b`)
+)
+
+// publicIdentifiers identify nothing about a workload and help the
+// diagnosis, so they are sent unchanged
+var publicIdentifiers = map[string]bool{
+	"default": true, "kube-system": true, "kube-public": true, "tekton-pipelines": true,
+	"github.com": true, "gitlab.com": true, "bitbucket.org": true, "docker.io": true, "quay.io": true,
+	"ghcr.io": true, "gcr.io": true, "registry.k8s.io": true, "registry.redhat.io": true,
+	"registry.access.redhat.com": true, "proxy.golang.org": true, "pypi.org": true,
+	"registry.npmjs.org": true, "repo.maven.apache.org": true, "golang.org": true, "tekton.dev": true,
+	"127.0.0.1": true, "0.0.0.0": true,
+}
+
+// sourceExtensions are file extensions that make file.ext:line look like
+// host:port
+var sourceExtensions = map[string]bool{
+	"go": true, "py": true, "js": true, "ts": true, "jsx": true, "tsx": true, "java": true, "kt": true, "rb": true,
+	"rs": true, "c": true, "h": true, "cc": true, "cpp": true, "cs": true, "php": true, "sh": true, "yaml": true,
+	"yml": true, "json": true, "xml": true, "txt": true, "log": true,
+}
+
+// publicSuffixes are API groups and domains kept like publicIdentifiers
+var publicSuffixes = []string{".tekton.dev", ".k8s.io", ".kubernetes.io", ".openshift.io"}
+
+func public(name string) bool {
+	if publicIdentifiers[name] || strings.HasPrefix(name, "openshift-") {
+		return true
+	}
+	for _, suffix := range publicSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Pseudonymizer replaces namespace, run, image and host names with stable
+// tokens (ns-1, run-1, image-A, host-A) and restores them in answers. The
+// same name always gets the same token, so follow-up questions stay
+// consistent.
+type Pseudonymizer struct {
+	mu     sync.Mutex
+	tokens map[string]string
+	names  map[string]string
+	counts map[string]int
+}
+
+// NewPseudonymizer returns an empty Pseudonymizer
+func NewPseudonymizer() *Pseudonymizer {
+	return &Pseudonymizer{tokens: map[string]string{}, names: map[string]string{}, counts: map[string]int{}}
+}
+
+// Pseudonymize returns text with workload identifiers replaced by tokens.
+// Known run and namespace names are only replaced where they are used as
+// identifiers, so a namespace called "build" leaves prose such as "the
+// build step" intact.
+func (p *Pseudonymizer) Pseudonymize(text string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Real names that look like tokens, e.g. a namespace called ns-2, get
+	// a token of their own so Restore cannot confuse them
+	text = pseudonym.ReplaceAllStringFunc(text, func(s string) string {
+		kind, _, _ := strings.Cut(s, "-")
+		return p.issue(kind, s)
+	})
+
+	for _, m := range runName.FindAllStringSubmatch(text, -1) {
+		p.token("run", m[1])
+	}
+	for _, m := range namespaceName.FindAllStringSubmatch(text, -1) {
+		p.token("ns", m[1])
+	}
+	text = imageRef.ReplaceAllStringFunc(text, func(s string) string { return p.token("image", s) })
+	host := func(s string) string { return p.token("host", strings.ToLower(s)) }
+	text = hostName.ReplaceAllStringFunc(text, host)
+	text = replaceGroup(urlHost, text, host)
+	text = replaceGroup(portHost, text, func(s string) string {
+		if sourceExtensions[strings.ToLower(s[strings.LastIndex(s, ".")+1:])] {
+			return s
+		}
+		return host(s)
+	})
+	text = ipv4.ReplaceAllStringFunc(text, host)
+	text = replaceGroup(runName, text, func(s string) string { return p.token("run", s) })
+	text = replaceGroup(namespaceName, text, func(s string) string { return p.token("ns", s) })
+
+	// Replace the other identifier uses of known runs and namespaces,
+	// longest first so "build" does not break up "build-x7k2". Escaped
+	// names were all replaced above.
+	var names []string
+	for name, token := range p.tokens {
+		if !strings.HasPrefix(token, "image-") && !strings.HasPrefix(token, "host-") && !pseudonym.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	for _, name := range names {
+		// Pods and their containers are named after the run, e.g.
+		// build-x7k2-pod; generated names are distinct enough to be
+		// replaced as such a prefix
+		prefix := strings.HasPrefix(p.tokens[name], "run-") && strings.ContainsAny(name, "-0123456789")
+		text = replaceIdentifier(text, name, p.tokens[name], prefix)
+	}
+	return text
+}
+
+// replaceGroup replaces the first group of each match of re with fn(group)
+func replaceGroup(re *regexp.Regexp, text string, fn func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range re.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(text[last:m[2]])
+		b.WriteString(fn(text[m[2]:m[3]]))
+		last = m[3]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// replaceIdentifier replaces name with token where it is quoted or part of
+// a path such as ns/name, and with prefix also where it starts a hyphenated
+// name such as build-x7k2-pod
+func replaceIdentifier(text, name, token string, prefix bool) string {
+	var b strings.Builder
+	last := 0
+	for i := 0; ; {
+		j := strings.Index(text[i:], name)
+		if j < 0 {
+			break
+		}
+		start, end := i+j, i+j+len(name)
+		i = end
+		var before, after byte
+		if start > 0 {
+			before = text[start-1]
+		}
+		if end < len(text) {
+			after = text[end]
+		}
+		asPrefix := prefix && !identChar(before) && after == '-'
+		if !asPrefix && (identChar(before) || identChar(after) || !(delimiter(before) || delimiter(after))) {
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(token)
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func identChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.'
+}
+
+// delimiter reports whether c sets off an identifier: a quote, a backtick
+// or the slash of a path
+func delimiter(c byte) bool {
+	return c == '\'' || c == '"' || c == '`' || c == '/'
+}
+
+// Restore replaces the tokens in text with the names they stand for
+func (p *Pseudonymizer) Restore(text string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return pseudonym.ReplaceAllStringFunc(text, func(token string) string {
+		if name, ok := p.names[token]; ok {
+			return name
+		}
+		return token
+	})
+}
+
+// token returns the token of name, handing out the next one of kind when
+// name is new. Public names and tokens are returned unchanged.
+func (p *Pseudonymizer) token(kind, name string) string {
+	if _, isToken := p.names[name]; isToken || public(name) {
+		return name
+	}
+	return p.issue(kind, name)
+}
+
+// issue returns the token of name, handing out the next one of kind when
+// name is new
+func (p *Pseudonymizer) issue(kind, name string) string {
+	if t, ok := p.tokens[name]; ok {
+		return t
+	}
+	p.counts[kind]++
+	var t string
+	switch kind {
+	case "image", "host":
+		t = kind + "-" + letters(p.counts[kind])
+	default:
+		t = fmt.Sprintf("%s-%d", kind, p.counts[kind])
+	}
+	p.tokens[name], p.names[t] = t, name
+	return t
+}
+
+// letters returns the spreadsheet-style column name of n: A..Z, AA, AB...
+func letters(n int) string {
+	var s string
+	for ; n > 0; n = (n - 1) / 26 {
+		s = string(rune('A'+(n-1)%26)) + s
+	}
+	return s
+}
+
+// WithPseudonyms returns llm sending queries through p and restoring the
+// names in its answers. Answers are not streamed, since a token can be
+// split across chunks.
+func WithPseudonyms(llm LLM, p *Pseudonymizer) LLM {
+	return &pseudonymizedLLM{LLM: llm, pseudonyms: p}
+}
+
+type pseudonymizedLLM struct {
+	LLM
+	pseudonyms *Pseudonymizer
+}
+
+func (p *pseudonymizedLLM) Analyze(ctx context.Context, query string) (string, error) {
+	resp, err := p.LLM.Analyze(ctx, p.pseudonyms.Pseudonymize(query))
+	if err != nil {
+		return "", err
+	}
+	return p.pseudonyms.Restore(resp), nil
+}
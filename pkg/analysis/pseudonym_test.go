@@ -0,0 +1,83 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import "testing"
+
+func TestPseudonymize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "quoted names",
+			text: "Why did TaskRun 'build-x7k2' in namespace 'team-a' fail?",
+			want: "Why did TaskRun 'run-1' in namespace 'ns-1' fail?",
+		},
+		{
+			name: "namespace field and path",
+			text: "namespace: team-a\nsee team-a/build-x7k2 and taskruns/build-x7k2",
+			want: "namespace: ns-1\nsee ns-1/run-1 and taskruns/run-1",
+		},
+		{
+			name: "ordinary word as namespace",
+			text: "TaskRun 'unit' in namespace 'build' failed. The build step ran unit tests.",
+			want: "TaskRun 'run-1' in namespace 'ns-1' failed. The build step ran unit tests.",
+		},
+		{
+			name: "name inside a longer identifier",
+			text: "namespace 'build' runs build-cache",
+			want: "namespace 'ns-1' runs build-cache",
+		},
+		{
+			name: "public namespace",
+			text: "namespace 'default' and namespace 'openshift-pipelines'",
+			want: "namespace 'default' and namespace 'openshift-pipelines'",
+		},
+		{
+			name: "hosts by structure",
+			text: "fetch https://git.acme.co.uk/repo from nexus.acme.de:8081 via 10.0.12.7, see main.go:42",
+			want: "fetch https://host-A/repo from host-B:8081 via host-C, see main.go:42",
+		},
+		{
+			name: "names that look like tokens",
+			text: "namespace 'ns-2' and namespace 'team-a' ran taskruns/run-1",
+			want: "namespace 'ns-1' and namespace 'ns-2' ran taskruns/run-1",
+		},
+		{
+			name: "pod named after the run",
+			text: "TaskRun 'build-x7k2' failed: pod build-x7k2-pod, container build-x7k2-fetch-step",
+			want: "TaskRun 'run-1' failed: pod run-1-pod, container run-1-fetch-step",
+		},
+		{
+			name: "images and hosts",
+			text: "pull registry.corp.example.com/payments/api:1.4.2 from vault.payments.internal",
+			want: "pull image-A from host-A",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPseudonymizer()
+			got := p.Pseudonymize(tt.text)
+			if got != tt.want {
+				t.Fatalf("Pseudonymize() = %q, want %q", got, tt.want)
+			}
+			if restored := p.Restore(got); restored != tt.text {
+				t.Fatalf("Restore() = %q, want %q", restored, tt.text)
+			}
+		})
+	}
+}
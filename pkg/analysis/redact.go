@@ -0,0 +1,148 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// redactedPrefix starts every marker that replaces a redacted value
+const redactedPrefix = "[REDACTED"
+
+// redaction masks one kind of sensitive value. When the pattern has a
+// group named "secret" only that group is replaced, so the key or header
+// name stays readable.
+type redaction struct {
+	kind    string
+	pattern *regexp.Regexp
+	// keep reports values that match but are not sensitive
+	keep func(value string) bool
+}
+
+// builtinRedactions cover credentials commonly printed by build tools and
+// the personal data found in commit metadata
+var builtinRedactions = []redaction{
+	{kind: "private-key", pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{kind: "jwt", pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{kind: "authorization", pattern: regexp.MustCompile(`(?i)\b(?:bearer|basic)\s+(?P<secret>[A-Za-z0-9\-._~+/]{8,}=*)`)},
+	{kind: "aws-access-key", pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{kind: "aws-secret-key", pattern: regexp.MustCompile(`(?i)aws_secret_access_key["']?\s*[:=]\s*["']?(?P<secret>[A-Za-z0-9/+=]{40})`)},
+	{kind: "github-token", pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{kind: "gitlab-token", pattern: regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`)},
+	{kind: "slack-token", pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{kind: "url-credentials", pattern: regexp.MustCompile(`(?i)\b[a-z][a-z0-9+.-]*://[^\s:/@]+:(?P<secret>[^\s@/]+)@`)},
+	{kind: "password", pattern: regexp.MustCompile(`(?i)(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|client[_-]?secret)["']?[ \t]*[:=][ \t]*["']?(?P<secret>[^\s"',;]+)`)},
+	{kind: "email", pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`), keep: func(v string) bool {
+		// git@github.com:org/repo is an SSH remote, not an address
+		return strings.HasPrefix(v, "git@")
+	}},
+}
+
+// Redactor masks credentials and personal data in text before it is sent
+// to a provider. A nil *Redactor applies the built-in rules.
+type Redactor struct {
+	rules []redaction
+}
+
+// NoRedaction sends text to the provider unchanged
+var NoRedaction = &Redactor{}
+
+var defaultRedactor = &Redactor{rules: builtinRedactions}
+
+// NewRedactor returns a Redactor applying the built-in rules and the given
+// Go regular expressions. A custom pattern with a group named "secret"
+// only masks that group.
+func NewRedactor(patterns ...string) (*Redactor, error) {
+	r := &Redactor{rules: append([]redaction{}, builtinRedactions...)}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		r.rules = append(r.rules, redaction{kind: "custom", pattern: re})
+	}
+	return r, nil
+}
+
+// Redact returns text with every sensitive value replaced by a
+// [REDACTED:<kind>] marker and the number of values replaced. Line breaks
+// inside a redacted value are kept so line counts do not change.
+func (r *Redactor) Redact(text string) (string, int) {
+	if r == nil {
+		r = defaultRedactor
+	}
+	n := 0
+	for _, rule := range r.rules {
+		matches := rule.pattern.FindAllStringSubmatchIndex(text, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		group := rule.pattern.SubexpIndex("secret")
+		var b strings.Builder
+		last := 0
+		for _, m := range matches {
+			start, end := m[0], m[1]
+			if group > 0 && m[2*group] >= 0 {
+				start, end = m[2*group], m[2*group+1]
+			}
+			value := text[start:end]
+			if value == "" || strings.HasPrefix(value, redactedPrefix) || (rule.keep != nil && rule.keep(value)) {
+				continue
+			}
+			b.WriteString(text[last:start])
+			b.WriteString(redactedPrefix + ":" + rule.kind + "]")
+			b.WriteString(strings.Repeat("\n", strings.Count(value, "\n")))
+			last = end
+			n++
+		}
+		b.WriteString(text[last:])
+		text = b.String()
+	}
+	return text, n
+}
+
+// WithRedaction returns llm sending every query through r first, so hints
+// and follow-up questions are masked like logs. The streaming support of
+// llm is kept.
+func WithRedaction(llm LLM, r *Redactor) LLM {
+	redacted := &redactedLLM{LLM: llm, redactor: r}
+	if s, ok := llm.(Streamer); ok {
+		return &redactedStreamer{redactedLLM: redacted, streamer: s}
+	}
+	return redacted
+}
+
+type redactedLLM struct {
+	LLM
+	redactor *Redactor
+}
+
+func (r *redactedLLM) Analyze(ctx context.Context, query string) (string, error) {
+	query, _ = r.redactor.Redact(query)
+	return r.LLM.Analyze(ctx, query)
+}
+
+type redactedStreamer struct {
+	*redactedLLM
+	streamer Streamer
+}
+
+func (r *redactedStreamer) AnalyzeStream(ctx context.Context, query string) (<-chan StreamChunk, error) {
+	query, _ = r.redactor.Redact(query)
+	return r.streamer.AnalyzeStream(ctx, query)
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"sort"
+	"sync"
+)
+
+// Factory creates the LLM of a registered provider. It is only called with
+// a Config that passed ValidateConfig.
+type Factory func(cfg Config) (LLM, error)
+
+// Validator returns the provider specific problems of cfg, each as a
+// *ConfigError
+type Validator func(cfg Config) []error
+
+type registration struct {
+	factory  Factory
+	validate Validator
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*registration{}
+)
+
+// Register makes a provider available to New under name. It panics if name
+// is empty, factory is nil or the name is already registered.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if name == "" || factory == nil {
+		panic("analysis: Register requires a name and a factory")
+	}
+	if _, dup := registry[name]; dup {
+		panic("analysis: Register called twice for provider " + name)
+	}
+	registry[name] = &registration{factory: factory}
+}
+
+// RegisterValidator attaches provider specific checks to a registered
+// provider so ValidateConfig reports them together with the common ones
+func RegisterValidator(name string, validate Validator) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	r, ok := registry[name]
+	if !ok {
+		panic("analysis: RegisterValidator called for unregistered provider " + name)
+	}
+	r.validate = validate
+}
+
+// Providers returns the names of the registered providers, sorted
+func Providers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookup(name string) (*registration, bool) {
+	if name == "" {
+		name = ProviderLightspeed
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	r, ok := registry[name]
+	return r, ok
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// postJSON sends body as JSON and decodes a 2xx response into out. Non-2xx
+// responses are returned as *APIError attributed to provider.
+func postJSON(ctx context.Context, client *http.Client, provider, url string, headers map[string]string, body, out interface{}) error {
+	respBody, err := post(ctx, client, provider, url, headers, body)
+	if err != nil {
+		return err
+	}
+	defer safeClose(respBody)
+
+	b, err := io.ReadAll(respBody)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", provider, err)
+	}
+	return nil
+}
+
+// postStream sends body as JSON and returns the body of a 2xx response for
+// the caller to read and close. The client timeout only bounds the wait for
+// the response headers, since a streamed answer may take longer to generate;
+// ctx cancels the rest. Non-2xx responses are returned as *APIError
+// attributed to provider.
+func postStream(ctx context.Context, client *http.Client, provider, url string, headers map[string]string, body interface{}) (io.ReadCloser, error) {
+	if client.Timeout <= 0 {
+		return post(ctx, client, provider, url, headers, body)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	timer := time.AfterFunc(client.Timeout, cancel)
+	streaming := *client
+	streaming.Timeout = 0
+
+	respBody, err := post(ctx, &streaming, provider, url, headers, body)
+	if !timer.Stop() {
+		if respBody != nil {
+			safeClose(respBody)
+		}
+		cancel()
+		return nil, fmt.Errorf("request to %s failed: no response within %s: %w", provider, client.Timeout, context.DeadlineExceeded)
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &cancelOnClose{ReadCloser: respBody, cancel: cancel}, nil
+}
+
+// cancelOnClose releases the context of a streamed body once it is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
+
+// post sends body as JSON and returns the body of a 2xx response for the
+// caller to read and close. Non-2xx responses are returned as *APIError
+// attributed to provider.
+func post(ctx context.Context, client *http.Client, provider, url string, headers map[string]string, body interface{}) (io.ReadCloser, error) {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", provider, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer safeClose(resp.Body)
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		return nil, newAPIError(provider, resp.StatusCode, respBody)
+	}
+	return resp.Body, nil
+}
+
+// lightspeedShape renders a completion in the response shape returned by
+// Lightspeed, so every provider is displayed by the same renderers.
+func lightspeedShape(text string, inputTokens, outputTokens int) (string, error) {
+	b, err := json.Marshal(map[string]interface{}{
+		"response":      text,
+		"input_tokens":  inputTokens,
+		"output_tokens": outputTokens,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode response: %w", err)
+	}
+	return string(b), nil
+}
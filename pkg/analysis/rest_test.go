@@ -0,0 +1,57 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPostStream_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow-headers" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte("first "))
+		w.(http.Flusher).Flush()
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte("second"))
+	}))
+	t.Cleanup(srv.Close)
+	client := &http.Client{Timeout: 100 * time.Millisecond}
+
+	body, err := postStream(context.Background(), client, "p", srv.URL+"/stream", nil, map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(body)
+	_ = body.Close()
+	if err != nil || string(b) != "first second" {
+		t.Fatalf("expected the answer to outlive the timeout, got %q (%v)", b, err)
+	}
+
+	if _, err := postStream(context.Background(), client, "p", srv.URL+"/slow-headers", nil, map[string]string{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a timeout waiting for the response, got %v", err)
+	}
+
+	if err := postJSON(context.Background(), client, "p", srv.URL+"/json", nil, map[string]string{}, &struct{}{}); err == nil {
+		t.Fatal("expected the timeout to bound the whole non-streamed response")
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/types"
+)
+
+// ProviderRules selects the offline rules engine, which needs no model
+const ProviderRules = "rules"
+
+// rulesConfidence is reported for a rule match: the signature is certain,
+// but a canned answer cannot weigh the rest of the log
+const rulesConfidence = 0.7
+
+// ErrNoRuleMatched is returned when no rule recognizes the log
+var ErrNoRuleMatched = errors.New("no known failure pattern matched the log")
+
+func init() {
+	Register(ProviderRules, func(cfg Config) (LLM, error) {
+		return NewRulesLLM(cfg.Patterns...), nil
+	})
+}
+
+// Rule maps a failure signature in a log to a canned diagnosis. The texts
+// may use text/template actions over a RuleMatch, e.g. {{.Groups.repo}}.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	// Reason, when set, must also occur in the log as a whole word (e.g.
+	// a Tekton or Kubernetes reason such as CreateContainerConfigError)
+	Reason    string
+	RootCause string
+	Analysis  string
+	Category  types.Category
+	Solutions []string
+
+	// reason is Reason compiled by LoadPatterns and NewRulesLLM
+	reason *regexp.Regexp
+}
+
+// compileReason returns the whole-word pattern of a rule reason
+func compileReason(reason string) *regexp.Regexp {
+	return regexp.MustCompile(`\b` + regexp.QuoteMeta(reason) + `\b`)
+}
+
+// reasonPattern returns the compiled Reason of r, compiling it for rules
+// built by hand
+func (r *Rule) reasonPattern() *regexp.Regexp {
+	if r.reason != nil {
+		return r.reason
+	}
+	return compileReason(r.Reason)
+}
+
+// BuiltinRules recognizes the most common Tekton failures. Earlier rules
+// take precedence.
+var BuiltinRules = []Rule{
+	{
+		Name:      "oom-killed",
+		Pattern:   regexp.MustCompile(`(?i)\bOOMKilled\b|exit(ed with)? (code|status):? 137\b`),
+		RootCause: "The step container was killed because it exceeded its memory limit (OOMKilled, exit code 137).",
+		Analysis:  "The kernel terminates a container that uses more memory than its limit. The step needs more memory, or the build needs to use less of it.",
+		Category:  types.CategoryResources,
+		Solutions: []string{
+			"Raise the memory limit of the step with computeResources.limits.memory (or stepSpecs/taskRunSpecs in the PipelineRun)",
+			"Reduce the memory used by the build, e.g. limit parallel jobs or the JVM heap (-Xmx)",
+			"Check for a LimitRange in the namespace that caps the default container memory",
+		},
+	},
+	{
+		Name:      "timeout",
+		Pattern:   regexp.MustCompile(`(?i)\b(TaskRunTimeout|PipelineRunTimeout)\b|failed to finish within`),
+		RootCause: "The run was stopped because it exceeded its timeout.",
+		Analysis:  "Tekton cancels a TaskRun or PipelineRun that runs longer than its timeout (one hour by default). The work was either slow or stuck waiting.",
+		Category:  types.CategoryTimeout,
+		Solutions: []string{
+			"Raise spec.timeouts.pipeline/tasks/finally on the PipelineRun or spec.timeout on the TaskRun",
+			"Look for a step waiting on the network, a lock or user input",
+			"Cache dependencies (e.g. with a workspace) to speed up the build",
+		},
+	},
+	{
+		Name:      "image-pull",
+		Pattern:   regexp.MustCompile(`(?i)\b(ImagePullBackOff|ErrImagePull|InvalidImageName)\b|manifest unknown|pull access denied`),
+		RootCause: "A step image could not be pulled.",
+		Analysis:  "The kubelet could not pull the image: the name or tag is wrong, the registry is unreachable, or the pull requires credentials the service account does not have.",
+		Category:  types.CategoryImage,
+		Solutions: []string{
+			"Check the image name and tag in the Task step (run `skopeo inspect docker://<image>`)",
+			"Link a registry pull secret to the run's service account (`oc secrets link <sa> <secret> --for=pull`)",
+			"Check that the cluster can reach the registry (proxy, mirror or ImageContentSourcePolicy)",
+		},
+	},
+	{
+		Name:      "command-not-found",
+		Pattern:   regexp.MustCompile(`(?i)command not found|sh: (line \d+: )?\S+: not found|exit(ed with)? (code|status):? 127\b|executable file not found`),
+		RootCause: "The step script runs a command that does not exist in the step image (exit code 127).",
+		Analysis:  "A shell returns 127 when a command is not on the PATH. The step image is probably not the one the script was written for.",
+		Category:  types.CategoryScript,
+		Solutions: []string{
+			"Use a step image that contains the command, or install it in the script",
+			"Check the spelling of the command and the PATH in the image",
+		},
+	},
+	{
+		Name:      "not-executable",
+		Pattern:   regexp.MustCompile(`(?i)exit(ed with)? (code|status):? 126\b|permission denied.*exec|exec format error`),
+		RootCause: "The step could not execute its script or binary (exit code 126).",
+		Analysis:  "The file exists but is not executable for the step's user, or was built for another CPU architecture.",
+		Category:  types.CategoryScript,
+		Solutions: []string{
+			"Make the file executable (chmod +x) or run it through its interpreter",
+			"Use an image built for the node architecture (e.g. amd64 vs arm64)",
+		},
+	},
+	{
+		Name:      "disk-full",
+		Pattern:   regexp.MustCompile(`(?i)no space left on device`),
+		RootCause: "The step ran out of disk space.",
+		Analysis:  "The workspace volume or the node's ephemeral storage is full.",
+		Category:  types.CategoryResources,
+		Solutions: []string{
+			"Increase the size of the workspace PersistentVolumeClaim (volumeClaimTemplate storage request)",
+			"Set computeResources.requests.ephemeral-storage so the step is scheduled on a node with enough space",
+			"Clean up build caches and artifacts during the run",
+		},
+	},
+	{
+		Name:      "untrusted-certificate",
+		Pattern:   regexp.MustCompile(`(?i)x509: certificate signed by unknown authority|certificate verify failed|self[- ]signed certificate`),
+		RootCause: "A step does not trust the TLS certificate of a server it connects to.",
+		Analysis:  "The server uses a certificate from a CA that is not in the step image's trust store, typically an internal CA.",
+		Category:  types.CategoryConfig,
+		Solutions: []string{
+			"Mount the trusted CA bundle into the step (e.g. a ConfigMap labeled config.openshift.io/inject-trusted-cabundle)",
+			"Point the tool at the CA bundle (SSL_CERT_FILE, GIT_SSL_CAINFO or the tool's own option)",
+		},
+	},
+	{
+		Name:      "git-auth",
+		Pattern:   regexp.MustCompile(`(?i)could not read Username|Authentication failed for|Permission denied \(publickey\)|terminal prompts disabled`),
+		RootCause: "Git could not authenticate to the repository.",
+		Analysis:  "The clone needs credentials, but none were provided to the step or they were rejected.",
+		Category:  types.CategoryAuth,
+		Solutions: []string{
+			"Provide credentials through the basic-auth or ssh-directory workspace of the git-clone task",
+			"Annotate the git secret with tekton.dev/git-0 and link it to the run's service account",
+			"Check that the token or key has read access to the repository",
+		},
+	},
+	{
+		Name:      "missing-workspace",
+		Pattern:   regexp.MustCompile(`(?i)workspace .* (is )?not provided|declared workspace .* not provided|expected workspace .* but`),
+		RootCause: "A workspace declared by the Task or Pipeline was not provided by the run.",
+		Analysis:  "Every non-optional workspace must be bound in the TaskRun or PipelineRun spec.workspaces.",
+		Category:  types.CategoryWorkspace,
+		Solutions: []string{
+			"Bind the workspace in spec.workspaces of the run (e.g. with a volumeClaimTemplate or emptyDir)",
+			"Mark the workspace optional: true in the Task if it is not always needed",
+		},
+	},
+	{
+		Name:      "missing-param",
+		Pattern:   regexp.MustCompile(`(?i)missing parameters?|param .* (is )?not provided|invalid input params`),
+		RootCause: "A parameter required by the Task or Pipeline was not provided.",
+		Analysis:  "Parameters without a default must be set by the run, and their types must match the declaration.",
+		Category:  types.CategoryParams,
+		Solutions: []string{
+			"Pass the parameter in spec.params of the run",
+			"Add a default to the parameter declaration",
+		},
+	},
+	{
+		Name:      "container-config",
+		Pattern:   regexp.MustCompile(`(?i)\bCreateContainerConfigError\b|secret "[^"]+" not found|configmap "[^"]+" not found|couldn't find key`),
+		RootCause: "The step container could not be created because a referenced Secret or ConfigMap is missing.",
+		Analysis:  "Environment variables or volumes of the step refer to a Secret, ConfigMap or key that does not exist in the namespace.",
+		Category:  types.CategoryConfig,
+		Solutions: []string{
+			"Create the missing Secret or ConfigMap in the run's namespace",
+			"Fix the name or key referenced in env/envFrom/volumes of the step",
+		},
+	},
+	{
+		Name:      "quota-exceeded",
+		Pattern:   regexp.MustCompile(`(?i)exceeded quota|forbidden: .*quota`),
+		RootCause: "The pod could not be created because the namespace ResourceQuota is exhausted.",
+		Analysis:  "The requests or limits of the TaskRun pod do not fit in the remaining quota of the namespace.",
+		Category:  types.CategoryResources,
+		Solutions: []string{
+			"Wait for other runs to finish or delete completed pods that still count against the quota",
+			"Lower the step computeResources requests, or ask for a larger ResourceQuota",
+		},
+	},
+}
+
+// RuleMatch is a rule that recognized a log
+type RuleMatch struct {
+	Rule *Rule
+	// Line is the log line the pattern matched on
+	Line string
+	// Groups holds the named capture groups of the pattern
+	Groups map[string]string
+}
+
+// Match returns the first rule whose pattern (and reason) occurs in log
+func Match(rules []Rule, log string) (*RuleMatch, bool) {
+	for i := range rules {
+		r := &rules[i]
+		if r.Reason != "" && !r.reasonPattern().MatchString(log) {
+			continue
+		}
+		loc := r.Pattern.FindStringSubmatchIndex(log)
+		if loc == nil {
+			continue
+		}
+		m := &RuleMatch{Rule: r, Groups: map[string]string{}}
+		for j, name := range r.Pattern.SubexpNames() {
+			if name != "" && loc[2*j] >= 0 {
+				m.Groups[name] = log[loc[2*j]:loc[2*j+1]]
+			}
+		}
+		start := strings.LastIndex(log[:loc[0]], "\n") + 1
+		end := strings.Index(log[loc[1]:], "\n")
+		if end == -1 {
+			end = len(log)
+		} else {
+			end += loc[1]
+		}
+		m.Line = strings.TrimSpace(log[start:end])
+		return m, true
+	}
+	return nil, false
+}
+
+// expand renders a rule text with the match; texts that are not templates
+// are returned unchanged
+func (m *RuleMatch) expand(text string) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+	t, err := template.New(m.Rule.Name).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return text
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, m); err != nil {
+		return text
+	}
+	return b.String()
+}
+
+// RulesLLM answers log explanations from rules without calling a model, so
+// the CLI stays useful air-gapped
+type RulesLLM struct {
+	rules []Rule
+}
+
+// NewRulesLLM returns a RulesLLM trying the given rules (e.g. an
+// organization's pattern catalog) before BuiltinRules
+func NewRulesLLM(rules ...Rule) *RulesLLM {
+	all := append(append([]Rule{}, rules...), BuiltinRules...)
+	for i := range all {
+		if all[i].Reason != "" && all[i].reason == nil {
+			all[i].reason = compileReason(all[i].Reason)
+		}
+	}
+	return &RulesLLM{rules: all}
+}
+
+// Analyze matches the log embedded by LogQuery and returns the diagnosis of
+// the matching rule in the Lightspeed response shape
+func (r *RulesLLM) Analyze(ctx context.Context, query string) (string, error) {
+	log, ok := logFromQuery(query)
+	if !ok {
+		return "", fmt.Errorf("the %s provider needs log text; use explain", ProviderRules)
+	}
+	m, ok := Match(r.rules, log)
+	if !ok {
+		return "", ErrNoRuleMatched
+	}
+
+	rootCause := m.expand(m.Rule.RootCause)
+	solutions := make([]string, len(m.Rule.Solutions))
+	for i, sol := range m.Rule.Solutions {
+		solutions[i] = m.expand(sol)
+	}
+	b, err := json.Marshal(map[string]interface{}{
+		"response":   rootCause,
+		"analysis":   strings.TrimSpace(m.expand(m.Rule.Analysis) + "\n\nMatched rule " + m.Rule.Name + " on: " + m.Line),
+		"root_cause": rootCause,
+		"category":   m.Rule.Category,
+		"solutions":  solutions,
+		"confidence": rulesConfidence,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode response: %w", err)
+	}
+	return string(b), nil
+}
+
+// Name implements LLM
+func (r *RulesLLM) Name() string {
+	return ProviderRules
+}
+
+// Model implements LLM
+func (r *RulesLLM) Model() string {
+	return ""
+}
+
+// logFromQuery returns the log block of a LogQuery
+func logFromQuery(query string) (string, bool) {
+	const open, closing = "\n\nLog:\n```\n", "\n```"
+	start := strings.Index(query, open)
+	if start == -1 {
+		return "", false
+	}
+	log := query[start+len(open):]
+	// The log may itself hold fences, so take the last one ending the
+	// query or followed by an appended section such as the hint
+	end := len(log)
+	for {
+		end = strings.LastIndex(log[:end], closing)
+		if end == -1 {
+			return "", false
+		}
+		if rest := log[end+len(closing):]; rest == "" || strings.HasPrefix(rest, "\n\n") {
+			return log[:end], true
+		}
+	}
+}
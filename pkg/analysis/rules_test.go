@@ -0,0 +1,64 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import "testing"
+
+func TestLogFromQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"plain", LogQuery("exit status 1"), "exit status 1"},
+		{"fenced log", LogQuery("cat <<EOF\n```\nbody\n```\nexit status 1"), "cat <<EOF\n```\nbody\n```\nexit status 1"},
+		{"with hint", WithHint(LogQuery("log with\n```\nfence"), "it broke after the upgrade"), "log with\n```\nfence"},
+	}
+	for _, tt := range tests {
+		got, ok := logFromQuery(tt.query)
+		if !ok || got != tt.want {
+			t.Errorf("%s: got %q, %v", tt.name, got, ok)
+		}
+	}
+	if _, ok := logFromQuery(TaskRunQuery("build", "ci")); ok {
+		t.Error("expected no log in a TaskRun query")
+	}
+}
+
+func TestMatch_Reason(t *testing.T) {
+	rules, err := LoadPatterns([]byte("patterns:\n- name: secret\n  pattern: 'secret \"[^\"]+\" not found'\n  reason: CreateContainerConfigError\n  root_cause: A secret is missing.\n  category: config\n  solutions: [create it]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules[0].reason == nil {
+		t.Fatal("expected LoadPatterns to compile the reason")
+	}
+	tests := []struct {
+		log  string
+		want bool
+	}{
+		{"CreateContainerConfigError: secret \"token\" not found", true},
+		{"secret \"token\" not found", false},
+		{"CreateContainerConfigErrors: secret \"token\" not found", false},
+	}
+	for _, tt := range tests {
+		// A hand-built rule matches the same way
+		for _, r := range []Rule{rules[0], {Name: "manual", Pattern: rules[0].Pattern, Reason: rules[0].Reason}} {
+			if _, ok := Match([]Rule{r}, tt.log); ok != tt.want {
+				t.Errorf("%s on %q: got %v, want %v", r.Name, tt.log, ok, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"embed"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// DefaultRunbookSections is how many runbook sections are added to a query
+const DefaultRunbookSections = 2
+
+// minRunbookTerms is how many distinct terms a section must share with the
+// failure text to be considered relevant
+const minRunbookTerms = 3
+
+//go:embed runbooks/*.md
+var runbookFS embed.FS
+
+// RunbookSection is a "## " section of a bundled Tekton runbook
+type RunbookSection struct {
+	Source string
+	Title  string
+	Text   string
+
+	terms map[string]int
+}
+
+var (
+	runbookOnce     sync.Once
+	runbookSections []*RunbookSection
+	runbookIDF      map[string]float64
+)
+
+// stopwords are frequent terms that carry no signal for retrieval
+var stopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true, "this": true,
+	"are": true, "was": true, "not": true, "when": true, "from": true, "its": true,
+	"run": true, "use": true, "can": true, "all": true, "has": true, "have": true,
+}
+
+// terms splits text into lower-cased words of three or more characters,
+// folding plurals so "directories" matches "directory"
+func terms(text string) map[string]int {
+	counts := map[string]int{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		switch {
+		case len(w) > 4 && strings.HasSuffix(w, "ies"):
+			w = strings.TrimSuffix(w, "ies") + "y"
+		case len(w) > 4 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss"):
+			w = strings.TrimSuffix(w, "s")
+		}
+		if len(w) >= 3 && !stopwords[w] {
+			counts[w]++
+		}
+	}
+	return counts
+}
+
+func loadRunbooks() {
+	entries, _ := runbookFS.ReadDir("runbooks")
+	df := map[string]int{}
+	for _, e := range entries {
+		b, err := runbookFS.ReadFile(path.Join("runbooks", e.Name()))
+		if err != nil {
+			continue
+		}
+		for _, part := range strings.Split(string(b), "\n## ") {
+			part = strings.TrimPrefix(strings.TrimSpace(part), "## ")
+			title, text, _ := strings.Cut(part, "\n")
+			if title == "" {
+				continue
+			}
+			s := &RunbookSection{
+				Source: e.Name(),
+				Title:  strings.TrimSpace(title),
+				Text:   strings.TrimSpace(text),
+				terms:  terms(part),
+			}
+			for t := range s.terms {
+				df[t]++
+			}
+			runbookSections = append(runbookSections, s)
+		}
+	}
+	runbookIDF = map[string]float64{}
+	for t, n := range df {
+		runbookIDF[t] = math.Log(float64(len(runbookSections)+1) / float64(n))
+	}
+}
+
+// RetrieveRunbooks returns up to k bundled runbook sections relevant to the
+// failure text, best match first
+func RetrieveRunbooks(text string, k int) []RunbookSection {
+	runbookOnce.Do(loadRunbooks)
+	query := terms(text)
+
+	type scored struct {
+		section *RunbookSection
+		score   float64
+	}
+	var matches []scored
+	for _, s := range runbookSections {
+		score, shared := 0.0, 0
+		for t := range query {
+			tf, ok := s.terms[t]
+			if !ok {
+				continue
+			}
+			shared++
+			score += runbookIDF[t] * (1 + math.Log(float64(tf)))
+		}
+		if shared >= minRunbookTerms {
+			matches = append(matches, scored{s, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	var out []RunbookSection
+	for i := 0; i < len(matches) && i < k; i++ {
+		// Weak runner-up matches mostly share generic terms
+		if matches[i].score < matches[0].score/2 {
+			break
+		}
+		out = append(out, *matches[i].section)
+	}
+	return out
+}
+
+// WithRunbooks appends runbook sections to a query as reference material
+func WithRunbooks(query string, sections []RunbookSection) string {
+	if len(sections) == 0 {
+		return query
+	}
+	var b strings.Builder
+	b.WriteString(query)
+	b.WriteString("\n\nReference material from the Tekton documentation. Use the exact field names it gives; ignore it if it is unrelated to this failure:")
+	for _, s := range sections {
+		b.WriteString("\n\n### " + s.Title + "\n" + s.Text)
+	}
+	return b.String()
+}
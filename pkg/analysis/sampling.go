@@ -0,0 +1,97 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// sampling holds the generation parameters sent by chat-style providers.
+// Unset values keep the provider default.
+type sampling struct {
+	maxTokens   int
+	temperature *float64
+	topP        *float64
+	stop        []string
+	// maxCompletionTokens and reasoningEffort are sent to OpenAI-style
+	// reasoning models, which count reasoning in the output limit
+	maxCompletionTokens int
+	reasoningEffort     string
+}
+
+func newSampling(cfg Config) sampling {
+	return sampling{
+		maxTokens:   cfg.MaxTokens,
+		temperature: cfg.Temperature,
+		topP:        cfg.TopP,
+		stop:        cfg.Stop,
+
+		maxCompletionTokens: cfg.MaxCompletionTokens,
+		reasoningEffort:     cfg.ReasoningEffort,
+	}
+}
+
+func (s sampling) isSet() bool {
+	return s.maxTokens != 0 || s.temperature != nil || s.topP != nil || len(s.stop) > 0 || s.isReasoning()
+}
+
+func (s sampling) isReasoning() bool {
+	return s.maxCompletionTokens != 0 || s.reasoningEffort != ""
+}
+
+// reasoningEfforts are the accepted values of reasoning_effort
+var reasoningEfforts = []string{"minimal", "low", "medium", "high"}
+
+// validateSampling checks the generation parameters of cfg
+func validateSampling(cfg Config) []error {
+	var errs []error
+	s := newSampling(cfg)
+	if s.isSet() && (cfg.Provider == "" || cfg.Provider == ProviderLightspeed) {
+		errs = append(errs, &ConfigError{Field: "sampling", Message: "lightspeed does not accept max tokens, temperature, top_p or stop sequences; configure them on the Lightspeed service"})
+	}
+	if s.isReasoning() && cfg.Provider != ProviderOpenAICompatible && cfg.Provider != ProviderAzureOpenAI {
+		errs = append(errs, &ConfigError{Field: "reasoning", Message: fmt.Sprintf("reasoning effort and max completion tokens are only sent by %s and %s", ProviderAzureOpenAI, ProviderOpenAICompatible)})
+	}
+	if s.reasoningEffort != "" && (s.temperature != nil || s.topP != nil) {
+		errs = append(errs, &ConfigError{Field: "reasoning effort", Message: "reasoning models reject temperature and top_p; drop them or the reasoning effort"})
+	}
+	if e := s.reasoningEffort; e != "" && !slices.Contains(reasoningEfforts, e) {
+		errs = append(errs, &ConfigError{Field: "reasoning effort", Message: fmt.Sprintf("must be one of %s, got %q", strings.Join(reasoningEfforts, ", "), e)})
+	}
+	if s.maxCompletionTokens < 0 {
+		errs = append(errs, &ConfigError{Field: "max completion tokens", Message: "must not be negative"})
+	}
+	if s.maxTokens != 0 && s.maxCompletionTokens != 0 {
+		errs = append(errs, &ConfigError{Field: "max tokens", Message: "set either max tokens or max completion tokens; reasoning models only accept max completion tokens"})
+	}
+	if s.maxTokens < 0 {
+		errs = append(errs, &ConfigError{Field: "max tokens", Message: "must not be negative"})
+	}
+	if t := s.temperature; t != nil && (*t < 0 || *t > 2) {
+		errs = append(errs, &ConfigError{Field: "temperature", Message: fmt.Sprintf("must be between 0 and 2, got %g", *t)})
+	}
+	if p := s.topP; p != nil && (*p <= 0 || *p > 1) {
+		errs = append(errs, &ConfigError{Field: "top_p", Message: fmt.Sprintf("must be greater than 0 and at most 1, got %g", *p)})
+	}
+	for _, stop := range s.stop {
+		if stop == "" {
+			errs = append(errs, &ConfigError{Field: "stop sequence", Message: "must not be empty"})
+			break
+		}
+	}
+	return errs
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidateConfig_Temperature(t *testing.T) {
+	tests := []struct {
+		provider    string
+		temperature float64
+		wantErr     bool
+	}{
+		{ProviderOpenAICompatible, 1.5, false},
+		{ProviderOpenAICompatible, 2.5, true},
+		{ProviderGemini, 2, false},
+		{ProviderAnthropic, 1, false},
+		{ProviderAnthropic, 1.5, true},
+		{ProviderAnthropic, -0.1, true},
+	}
+	for _, tt := range tests {
+		temperature := tt.temperature
+		err := ValidateConfig(Config{Provider: tt.provider, APIKey: "k", BaseURL: "http://localhost", Model: "m", Temperature: &temperature})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s with temperature %g: got error %v, want error %v", tt.provider, tt.temperature, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateConfig_SafetyThreshold(t *testing.T) {
+	tests := []struct {
+		provider  string
+		threshold string
+		wantErr   bool
+	}{
+		{ProviderGemini, "", false},
+		{ProviderGemini, "BLOCK_ONLY_HIGH", false},
+		{ProviderGemini, "block_only_high", true},
+		{ProviderOpenAICompatible, "BLOCK_ONLY_HIGH", true},
+	}
+	for _, tt := range tests {
+		err := ValidateConfig(Config{Provider: tt.provider, APIKey: "k", BaseURL: "http://localhost", Model: "m", SafetyThreshold: tt.threshold})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s with threshold %q: got error %v, want error %v", tt.provider, tt.threshold, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNew_SamplingParameters(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	zero, topP := 0.0, 0.9
+	llm, err := New(Config{
+		Provider:    ProviderOpenAICompatible,
+		Model:       "m",
+		BaseURL:     srv.URL,
+		MaxTokens:   256,
+		Temperature: &zero,
+		TopP:        &topP,
+		Stop:        []string{"###"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := llm.Analyze(context.Background(), "q"); err != nil {
+		t.Fatal(err)
+	}
+	if got["max_tokens"] != 256.0 || got["temperature"] != 0.0 || got["top_p"] != 0.9 || len(got["stop"].([]interface{})) != 1 {
+		t.Fatalf("sampling parameters not sent: %v", got)
+	}
+
+	if _, err := New(Config{Temperature: &zero}); err == nil {
+		t.Fatal("expected lightspeed to reject sampling parameters")
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// SelfDiagnosisError wraps a failure of tekton-assist itself (as opposed to
+// the run being diagnosed) with concrete steps for the operator
+type SelfDiagnosisError struct {
+	Err         error
+	Remediation []string
+}
+
+func (e *SelfDiagnosisError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Err.Error())
+	if len(e.Remediation) > 0 {
+		b.WriteString("\nTo fix this:")
+		for _, r := range e.Remediation {
+			b.WriteString("\n  - " + r)
+		}
+	}
+	return b.String()
+}
+
+func (e *SelfDiagnosisError) Unwrap() error {
+	return e.Err
+}
+
+// apiKeyEnvs names the credential environment variable of each provider
+var apiKeyEnvs = map[string]string{
+	ProviderGemini:           GeminiAPIKeyEnv,
+	ProviderAnthropic:        AnthropicAPIKeyEnv,
+	ProviderAzureOpenAI:      AzureOpenAIAPIKeyEnv,
+	ProviderOpenAICompatible: OpenAIAPIKeyEnv,
+}
+
+// withRemediation returns err wrapped in a SelfDiagnosisError when a known
+// remediation exists for it, and err unchanged otherwise
+func withRemediation(provider string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if steps := remediationFor(provider, err); len(steps) > 0 {
+		return &SelfDiagnosisError{Err: err, Remediation: steps}
+	}
+	return err
+}
+
+func remediationFor(provider string, err error) []string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return remediationForCode(provider, apiErr.Code)
+	}
+
+	if errors.Is(err, ErrReasoningExhausted) {
+		return []string{
+			"raise --max-completion-tokens so the answer fits after the reasoning",
+			"or lower --reasoning-effort",
+		}
+	}
+
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	switch {
+	case errors.As(err, &unknownAuthority):
+		trust := "the service certificate is not trusted; pass its CA bundle with --ca-file, or --ca-configmap [namespace/]name[:key] to read it from the cluster"
+		if env := CAFileEnv(provider); env != "" {
+			trust += ", or set " + env
+		}
+		return []string{
+			trust,
+			"for local testing only, pass --insecure-skip-tls-verify (-k)",
+		}
+	case errors.As(err, &hostnameErr):
+		return []string{
+			"the service certificate is not valid for this host; use a URL with a host name the certificate lists",
+			"for local testing only, pass --insecure-skip-tls-verify (-k)",
+		}
+	case errors.Is(err, syscall.ECONNREFUSED):
+		if provider == ProviderLightspeed || provider == "" {
+			return []string{
+				"check that --lightspeed-url points at a running Lightspeed service",
+				"if the service runs in-cluster, expose it locally, e.g. `oc port-forward -n openshift-lightspeed svc/lightspeed-app-server 8443:8443`",
+			}
+		}
+		return []string{"check that the provider endpoint is reachable from this machine"}
+	case errors.Is(err, context.DeadlineExceeded) || isTimeout(err):
+		return []string{
+			"the provider did not answer in time; retry with a larger --timeout",
+		}
+	}
+	return nil
+}
+
+func remediationForCode(provider string, code ErrorCode) []string {
+	switch code {
+	case CodeUnauthorized:
+		if env, ok := apiKeyEnvs[provider]; ok {
+			return []string{"set a valid API key in " + env}
+		}
+		return []string{
+			"log in again (`oc login`) or pass a fresh token with --token, --token-file or LIGHTSPEED_TOKEN",
+		}
+	case CodeForbidden:
+		if provider == ProviderLightspeed || provider == "" {
+			return []string{
+				"ask a cluster admin to grant your user query access to OpenShift Lightspeed",
+			}
+		}
+		return []string{"check that the API key is allowed to use the configured model"}
+	case CodeNotFound:
+		return []string{
+			"check the service URL and, for hosted providers, the --model (or deployment) name",
+		}
+	case CodeRateLimited:
+		return []string{"the provider is throttling requests; wait and retry, or lower request concurrency"}
+	case CodeUnavailable:
+		return []string{"the provider is temporarily unavailable; check its status and retry"}
+	}
+	return nil
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
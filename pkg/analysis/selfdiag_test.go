@@ -0,0 +1,42 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"crypto/x509"
+	"strings"
+	"testing"
+)
+
+func TestWithRemediation_Certificates(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		err      error
+		want     []string
+	}{
+		{"unknown authority", ProviderOpenAICompatible, x509.UnknownAuthorityError{}, []string{"--ca-file", "--ca-configmap", "OPENAI_CA_FILE", "--insecure-skip-tls-verify"}},
+		{"unknown authority without a CA variable", ProviderGemini, x509.UnknownAuthorityError{}, []string{"--ca-file", "--ca-configmap"}},
+		{"wrong host", ProviderLightspeed, x509.HostnameError{Certificate: &x509.Certificate{}, Host: "lightspeed"}, []string{"not valid for this host"}},
+	}
+	for _, tt := range tests {
+		err := withRemediation(tt.provider, tt.err)
+		for _, want := range tt.want {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: expected %q in %q", tt.name, want, err)
+			}
+		}
+	}
+}
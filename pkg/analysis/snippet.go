@@ -0,0 +1,75 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"strings"
+)
+
+// DefaultSnippetLines bounds how many log lines are sent to a provider
+const DefaultSnippetLines = 80
+
+// errorMarkers are lower-cased substrings that usually point at the failure
+var errorMarkers = []string{
+	"error", "fatal", "failed", "failure", "panic", "exception", "traceback",
+	"denied", "not found", "no such file", "permission", "timed out", "timeout",
+	"oomkilled", "exit code", "exit status", "unauthorized", "forbidden",
+}
+
+// ExtractSnippet returns the most relevant part of a log: every line that
+// looks like an error plus a couple of lines of context, followed by the tail
+// of the log. The result never exceeds maxLines lines.
+func ExtractSnippet(log string, maxLines int) string {
+	if maxLines <= 0 {
+		maxLines = DefaultSnippetLines
+	}
+	lines := strings.Split(strings.TrimRight(log, "\n"), "\n")
+	if len(lines) <= maxLines {
+		return strings.Join(lines, "\n")
+	}
+
+	const contextLines = 2
+	keep := make([]bool, len(lines))
+	for i, line := range lines {
+		lower := strings.ToLower(line)
+		for _, marker := range errorMarkers {
+			if strings.Contains(lower, marker) {
+				for j := max(0, i-contextLines); j <= min(len(lines)-1, i+contextLines); j++ {
+					keep[j] = true
+				}
+				break
+			}
+		}
+	}
+
+	// The tail usually holds the final error, so always keep a quarter of
+	// the budget for it.
+	tail := maxLines / 4
+	for i := len(lines) - tail; i < len(lines); i++ {
+		keep[i] = true
+	}
+
+	var selected []string
+	for i, k := range keep {
+		if k {
+			selected = append(selected, lines[i])
+		}
+	}
+	if len(selected) > maxLines {
+		// Prefer the latest matches: earlier errors are often noise
+		selected = selected[len(selected)-maxLines:]
+	}
+	return strings.Join(selected, "\n")
+}
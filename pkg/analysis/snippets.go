@@ -0,0 +1,212 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// SnippetsKey is the top-level response field holding the checks of the
+// YAML snippets in the answer
+const SnippetsKey = "yaml_snippets"
+
+// SnippetCheck reports whether a YAML snippet of the answer can be applied
+// as written
+type SnippetCheck struct {
+	// Index is the 1-based position of the snippet in the answer
+	Index int `json:"index"`
+	// Kind is the Tekton kind of the snippet, empty for fragments
+	Kind     string   `json:"kind,omitempty"`
+	Valid    bool     `json:"valid"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// fence matches a fenced block and its language label
+var fence = regexp.MustCompile("(?s)```([\\w+-]*)[ \t]*\n(.*?)```")
+
+// tektonFields are the spec fields of the tekton.dev/v1 kinds; a field
+// outside these is a typo or belongs to another API version
+var tektonFields = map[string][]string{
+	"Task":        {"description", "displayName", "params", "workspaces", "results", "steps", "sidecars", "stepTemplate", "volumes"},
+	"Pipeline":    {"description", "displayName", "params", "workspaces", "results", "tasks", "finally"},
+	"TaskRun":     {"serviceAccountName", "taskRef", "taskSpec", "params", "workspaces", "timeout", "podTemplate", "status", "statusMessage", "retries", "debug", "stepSpecs", "sidecarSpecs", "computeResources", "managedBy"},
+	"PipelineRun": {"pipelineRef", "pipelineSpec", "params", "workspaces", "timeouts", "taskRunTemplate", "taskRunSpecs", "status", "managedBy"},
+}
+
+// stepFields are the fields of a tekton.dev/v1 step
+var stepFields = []string{"name", "displayName", "image", "command", "args", "workingDir", "env", "envFrom", "computeResources",
+	"volumeMounts", "volumeDevices", "imagePullPolicy", "securityContext", "script", "timeout", "workspaces", "onError",
+	"stdoutConfig", "stderrConfig", "ref", "params", "results", "when"}
+
+// pipelineTaskFields are the fields of a tekton.dev/v1 pipeline task
+var pipelineTaskFields = []string{"name", "displayName", "description", "taskRef", "taskSpec", "pipelineRef", "pipelineSpec",
+	"when", "runAfter", "retries", "params", "matrix", "workspaces", "timeout", "onError"}
+
+// CheckSnippets parses the YAML snippets fenced in text and checks Tekton
+// resources against the tekton.dev/v1 field names and required fields.
+// Snippets without a Tekton apiVersion, e.g. a single step, are only
+// checked for syntax. Fences without a language are considered only when
+// they contain apiVersion, so shell snippets are skipped.
+func CheckSnippets(text string) []SnippetCheck {
+	var checks []SnippetCheck
+	for _, m := range fence.FindAllStringSubmatch(text, -1) {
+		lang, body := strings.ToLower(m[1]), m[2]
+		if lang != "yaml" && lang != "yml" && (lang != "" || !strings.Contains(body, "apiVersion:")) {
+			continue
+		}
+		check := SnippetCheck{Index: len(checks) + 1}
+		for _, doc := range strings.Split(body, "\n---") {
+			if strings.TrimSpace(doc) == "" {
+				continue
+			}
+			var obj interface{}
+			if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+				check.Problems = append(check.Problems, "invalid YAML: "+strings.TrimPrefix(err.Error(), "yaml: "))
+				continue
+			}
+			if m, ok := obj.(map[interface{}]interface{}); ok {
+				kind, problems := checkTekton(m)
+				if kind != "" {
+					check.Kind = kind
+				}
+				check.Problems = append(check.Problems, problems...)
+			}
+		}
+		check.Valid = len(check.Problems) == 0
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkSnippets sets r.Snippets from the text of the answer
+func (r *Result) checkSnippets() {
+	a := ParseAnswer(r.Response)
+	r.Snippets = CheckSnippets(strings.Join(append([]string{a.Summary, a.Analysis}, a.Solutions...), "\n"))
+}
+
+// checkTekton returns the kind of a tekton.dev object and its problems
+func checkTekton(obj map[interface{}]interface{}) (string, []string) {
+	apiVersion, _ := obj["apiVersion"].(string)
+	kind, _ := obj["kind"].(string)
+	if !strings.HasPrefix(apiVersion, "tekton.dev/") {
+		return "", nil
+	}
+	var problems []string
+	version := strings.TrimPrefix(apiVersion, "tekton.dev/")
+	switch version {
+	case "v1", "v1beta1":
+	default:
+		problems = append(problems, fmt.Sprintf("unknown apiVersion %s (expected tekton.dev/v1)", apiVersion))
+	}
+	fields, known := tektonFields[kind]
+	if !known {
+		return kind, append(problems, fmt.Sprintf("unknown kind %q", kind))
+	}
+	if md, _ := obj["metadata"].(map[interface{}]interface{}); md["name"] == nil && md["generateName"] == nil {
+		problems = append(problems, "metadata.name or metadata.generateName is required")
+	}
+	spec, ok := obj["spec"].(map[interface{}]interface{})
+	if !ok {
+		return kind, append(problems, "spec is required")
+	}
+	// Field names changed between v1beta1 and v1; only v1 is checked
+	if version == "v1" {
+		problems = append(problems, unknownFields("spec", spec, fields, renamedFields[kind])...)
+	}
+
+	switch kind {
+	case "Task":
+		steps, _ := spec["steps"].([]interface{})
+		if len(steps) == 0 {
+			problems = append(problems, "spec.steps must list at least one step")
+		}
+		for i, s := range steps {
+			step, _ := s.(map[interface{}]interface{})
+			if step["image"] == nil && step["ref"] == nil {
+				problems = append(problems, fmt.Sprintf("spec.steps[%d] needs an image", i))
+			}
+			if version == "v1" {
+				problems = append(problems, unknownFields(fmt.Sprintf("spec.steps[%d]", i), step, stepFields, renamedFields["Step"])...)
+			}
+		}
+	case "Pipeline":
+		tasks, _ := spec["tasks"].([]interface{})
+		if len(tasks) == 0 {
+			problems = append(problems, "spec.tasks must list at least one task")
+		}
+		for i, t := range append(tasks, asList(spec["finally"])...) {
+			task, _ := t.(map[interface{}]interface{})
+			if task["name"] == nil {
+				problems = append(problems, fmt.Sprintf("pipeline task %d needs a name", i+1))
+			}
+			if task["taskRef"] == nil && task["taskSpec"] == nil && task["pipelineRef"] == nil && task["pipelineSpec"] == nil {
+				problems = append(problems, fmt.Sprintf("pipeline task %d needs taskRef or taskSpec", i+1))
+			}
+			if version == "v1" {
+				problems = append(problems, unknownFields(fmt.Sprintf("pipeline task %d", i+1), task, pipelineTaskFields, nil)...)
+			}
+		}
+	case "TaskRun":
+		if spec["taskRef"] == nil && spec["taskSpec"] == nil {
+			problems = append(problems, "spec.taskRef or spec.taskSpec is required")
+		}
+	case "PipelineRun":
+		if spec["pipelineRef"] == nil && spec["pipelineSpec"] == nil {
+			problems = append(problems, "spec.pipelineRef or spec.pipelineSpec is required")
+		}
+	}
+	return kind, problems
+}
+
+// renamedFields are v1beta1 fields models still suggest, by kind, with
+// their tekton.dev/v1 replacement
+var renamedFields = map[string]map[string]string{
+	"Step":        {"resources": "computeResources"},
+	"TaskRun":     {"resources": "computeResources"},
+	"PipelineRun": {"timeout": "timeouts", "serviceAccountName": "taskRunTemplate.serviceAccountName"},
+}
+
+// unknownFields reports the keys of obj that are not in fields, naming
+// the replacement of renamed fields
+func unknownFields(path string, obj map[interface{}]interface{}, fields []string, renamed map[string]string) []string {
+	allowed := map[string]bool{}
+	for _, f := range fields {
+		allowed[f] = true
+	}
+	var problems []string
+	for k := range obj {
+		name := fmt.Sprint(k)
+		if allowed[name] {
+			continue
+		}
+		problem := fmt.Sprintf("%s.%s is not a tekton.dev/v1 field", path, name)
+		if v1, ok := renamed[name]; ok {
+			problem += fmt.Sprintf(" (use %s)", v1)
+		}
+		problems = append(problems, problem)
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+func asList(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
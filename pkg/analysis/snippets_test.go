@@ -0,0 +1,81 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckSnippets(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    int
+		problem string
+	}{
+		{
+			name: "valid task",
+			text: "```yaml\napiVersion: tekton.dev/v1\nkind: Task\nmetadata:\n  name: t\nspec:\n  steps:\n    - name: s\n      image: alpine\n```",
+			want: 1,
+		},
+		{
+			name:    "v1beta1 field in v1",
+			text:    "```yaml\napiVersion: tekton.dev/v1\nkind: TaskRun\nmetadata:\n  name: r\nspec:\n  taskRef:\n    name: t\n  resources: {}\n```",
+			want:    1,
+			problem: "spec.resources is not a tekton.dev/v1 field (use computeResources)",
+		},
+		{
+			name:    "invalid yaml",
+			text:    "```yml\nsteps: [\n```",
+			want:    1,
+			problem: "invalid YAML",
+		},
+		{
+			name: "fragment",
+			text: "```yaml\nsecret:\n  secretName: git-credentials\n```",
+			want: 1,
+		},
+		{
+			name: "shell and unlabeled fences",
+			text: "```sh\nkubectl get pods\n```\n```\nnot yaml: [\n```",
+			want: 0,
+		},
+		{
+			name:    "unlabeled manifest",
+			text:    "```\napiVersion: tekton.dev/v1\nkind: Pipeline\nmetadata:\n  name: p\nspec:\n  tasks: []\n```",
+			want:    1,
+			problem: "spec.tasks must list at least one task",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := CheckSnippets(tt.text)
+			if len(checks) != tt.want {
+				t.Fatalf("CheckSnippets() = %+v, want %d checks", checks, tt.want)
+			}
+			if tt.want == 0 {
+				return
+			}
+			problems := strings.Join(checks[0].Problems, "; ")
+			if tt.problem == "" && !checks[0].Valid {
+				t.Fatalf("expected a valid snippet, got %s", problems)
+			}
+			if tt.problem != "" && !strings.Contains(problems, tt.problem) {
+				t.Fatalf("expected problem %q, got %q", tt.problem, problems)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// StreamChunk is a piece of a streamed answer. A chunk with Err set is the
+// last one sent before the channel is closed.
+type StreamChunk struct {
+	Text string
+	Err  error
+}
+
+// Streamer is implemented by providers that can return the answer while it
+// is being generated
+type Streamer interface {
+	// AnalyzeStream sends the query and returns a channel of answer chunks
+	// that is closed when the answer is complete. Errors before the first
+	// byte is received are returned directly.
+	AnalyzeStream(ctx context.Context, query string) (<-chan StreamChunk, error)
+}
+
+// Stream writes the answer to w as it is generated when llm implements
+// Streamer and reports whether it did. The returned Result carries the full
+// answer in the Lightspeed response shape. Providers without streaming
+// support are run with Run and nothing is written to w.
+func Stream(ctx context.Context, llm LLM, query string, w io.Writer) (*Result, bool, error) {
+	s, ok := llm.(Streamer)
+	if !ok {
+		res, err := Run(ctx, llm, query)
+		return res, false, err
+	}
+
+	start := time.Now()
+	chunks, err := s.AnalyzeStream(ctx, query)
+	if err != nil {
+		return nil, true, withRemediation(llm.Name(), err)
+	}
+	var answer strings.Builder
+	for c := range chunks {
+		if c.Err != nil {
+			return nil, true, withRemediation(llm.Name(), c.Err)
+		}
+		answer.WriteString(c.Text)
+		if _, err := io.WriteString(w, c.Text); err != nil {
+			return nil, true, err
+		}
+	}
+
+	resp, err := lightspeedShape(answer.String(), 0, 0)
+	if err != nil {
+		return nil, true, err
+	}
+	res := &Result{
+		Response:   resp,
+		Structured: structured(resp),
+		Metadata: Metadata{
+			Provider:      llm.Name(),
+			Model:         llm.Model(),
+			PromptVersion: PromptVersion,
+			PromptHash:    PromptHash(query),
+			DurationMS:    time.Since(start).Milliseconds(),
+		},
+	}
+	res.Assess("")
+	res.checkSnippets()
+	return res, true, nil
+}
+
+// streamText forwards body to a chunk channel as it is read
+func streamText(ctx context.Context, body io.ReadCloser) <-chan StreamChunk {
+	ch := make(chan StreamChunk)
+	go func() {
+		defer close(ch)
+		defer safeClose(body)
+		buf := make([]byte, 4096)
+		for {
+			n, err := body.Read(buf)
+			if n > 0 && !send(ctx, ch, StreamChunk{Text: string(buf[:n])}) {
+				return
+			}
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				send(ctx, ch, StreamChunk{Err: fmt.Errorf("stream interrupted: %w", err)})
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// streamChatSSE forwards the content deltas of an OpenAI-style server-sent
+// event stream. A stream that reaches the output limit without content
+// ends with ErrReasoningExhausted.
+func streamChatSSE(ctx context.Context, provider string, body io.ReadCloser) <-chan StreamChunk {
+	ch := make(chan StreamChunk)
+	go func() {
+		defer close(ch)
+		defer safeClose(body)
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		answered := false
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data:")
+			if !ok {
+				continue
+			}
+			data = strings.TrimSpace(data)
+			if data == "[DONE]" {
+				return
+			}
+			var event struct {
+				Choices []struct {
+					Delta        chatMessage `json:"delta"`
+					FinishReason string      `json:"finish_reason"`
+				} `json:"choices"`
+			}
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				send(ctx, ch, StreamChunk{Err: fmt.Errorf("failed to decode %s stream event: %w", provider, err)})
+				return
+			}
+			if len(event.Choices) == 0 {
+				continue
+			}
+			choice := event.Choices[0]
+			if choice.FinishReason == "length" && !answered {
+				send(ctx, ch, StreamChunk{Err: reasoningExhausted(provider, 0)})
+				return
+			}
+			if choice.Delta.Content == "" {
+				continue
+			}
+			answered = true
+			if !send(ctx, ch, StreamChunk{Text: choice.Delta.Content}) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			send(ctx, ch, StreamChunk{Err: fmt.Errorf("stream interrupted: %w", err)})
+		}
+	}()
+	return ch
+}
+
+// send delivers c unless ctx is done first
+func send(ctx context.Context, ch chan<- StreamChunk, c StreamChunk) bool {
+	select {
+	case ch <- c:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStream_Lightspeed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/streaming_query" {
+			http.NotFound(w, r)
+			return
+		}
+		for _, tok := range []string{"The step ", "ran out ", "of memory."} {
+			_, _ = w.Write([]byte(tok))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	llm, err := New(Config{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	res, streamed, err := Stream(context.Background(), llm, "why did it fail?", &out)
+	if err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	if !streamed || out.String() != "The step ran out of memory." {
+		t.Fatalf("unexpected stream output %q (streamed=%v)", out.String(), streamed)
+	}
+	if answer := ParseAnswer(res.Response); answer.Summary != "The step ran out of memory." {
+		t.Fatalf("unexpected result: %+v", answer)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// SystemPromptEnv holds the default system prompt
+const SystemPromptEnv = "TKN_ASSIST_SYSTEM_PROMPT"
+
+// SystemPromptData are the variables of a system prompt template
+type SystemPromptData struct {
+	// Cluster is the API server host of the kubeconfig context, e.g.
+	// api.prod.example.com
+	Cluster string
+	// Context is the name of the kubeconfig context
+	Context string
+	// Vars are organization specific values such as naming or
+	// escalation conventions, set with --prompt-var
+	Vars map[string]string
+}
+
+// RenderSystemPrompt expands a system prompt template such as "You debug
+// Tekton runs on {{.Cluster}}. {{.Vars.conventions}}". Text without
+// template actions is returned unchanged. Referencing a variable that is
+// not set is an error, so typos do not silently drop guidance.
+func RenderSystemPrompt(text string, data SystemPromptData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	t, err := template.New("system-prompt").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid system prompt template: %w", err)
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("cannot render system prompt: %w", err)
+	}
+	return b.String(), nil
+}
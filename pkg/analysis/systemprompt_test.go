@@ -0,0 +1,35 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import "testing"
+
+func TestRenderSystemPrompt(t *testing.T) {
+	data := SystemPromptData{Cluster: "api.prod.example.com", Context: "prod", Vars: map[string]string{"conventions": "x"}}
+	tests := []struct {
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{"Runs on {{.Cluster}} ({{.Context}}). Follow {{.Vars.conventions}}", "Runs on api.prod.example.com (prod). Follow x", false},
+		{"Follow {{.Vars.conventoins}}", "", true},
+	}
+	for _, tt := range tests {
+		got, err := RenderSystemPrompt(tt.tmpl, data)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%q: got %q, %v", tt.tmpl, got, err)
+		}
+	}
+}
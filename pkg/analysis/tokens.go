@@ -0,0 +1,96 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultSnippetTokens bounds the estimated size of a log snippet sent to a
+// provider
+const DefaultSnippetTokens = 2000
+
+// MinSnippetTokens is the smallest useful snippet budget; below it little
+// more than the omission marker fits
+const MinSnippetTokens = 32
+
+// pretokens splits text the way BPE tokenizers pre-split it before merging:
+// words, short digit groups, punctuation runs and whitespace
+var pretokens = regexp.MustCompile(`[A-Za-z]+|[0-9]{1,3}|[^\sA-Za-z0-9]+|\s+`)
+
+// EstimateTokens approximates the number of tokens a BPE tokenizer such as
+// cl100k produces for text. It errs on the high side so budgets hold for
+// the common providers.
+func EstimateTokens(text string) int {
+	n := 0
+	for _, piece := range pretokens.FindAllString(text, -1) {
+		switch c := piece[0]; {
+		case c == ' ' && len(piece) == 1:
+			// A single space merges into the following word
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			n++
+		case (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z'):
+			n += (len(piece) + 3) / 4
+		case c >= '0' && c <= '9':
+			n++
+		default:
+			// Symbols rarely merge beyond pairs; multi-byte runes count
+			// per byte pair
+			n += (len(piece) + 1) / 2
+		}
+	}
+	return n
+}
+
+// FitTokens trims a snippet to an estimated token budget. Earlier lines are
+// dropped first since the end of a log usually holds the final error; a
+// last line that alone exceeds the budget is cut from the front.
+func FitTokens(snippet string, budget int) string {
+	if budget <= 0 {
+		budget = DefaultSnippetTokens
+	}
+	if EstimateTokens(snippet) <= budget {
+		return snippet
+	}
+
+	lines := strings.Split(snippet, "\n")
+	// Reserve room for the omission marker
+	budget -= EstimateTokens(fmt.Sprintf("[%d earlier lines omitted]\n", len(lines)))
+	if budget < 1 {
+		budget = 1
+	}
+
+	used, first := 0, len(lines)
+	for i := len(lines) - 1; i >= 0; i-- {
+		cost := EstimateTokens(lines[i]) + 1
+		if used+cost > budget {
+			break
+		}
+		used += cost
+		first = i
+	}
+
+	if first == len(lines) {
+		// Cut whole runes, at least one per pass
+		last := []rune(lines[len(lines)-1])
+		for len(last) > 0 && EstimateTokens(string(last)) > budget {
+			last = last[max(len(last)/4, 1):]
+		}
+		return fmt.Sprintf("[%d earlier lines omitted]\n%s", len(lines)-1, string(last))
+	}
+	return fmt.Sprintf("[%d earlier lines omitted]\n%s", first, strings.Join(lines[first:], "\n"))
+}
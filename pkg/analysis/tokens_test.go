@@ -0,0 +1,60 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestFitTokens(t *testing.T) {
+	lines := strings.Repeat("error: something went wrong here\n", 10)
+	tests := []struct {
+		name    string
+		snippet string
+		budget  int
+		want    string
+	}{
+		{"fits", "error: boom", 10, "error: boom"},
+		{"keeps tail", lines + "fatal: out of memory", 20, "fatal: out of memory"},
+		{"tiny budget", lines + "!!!!!!!!!!!!!!!!", 3, "earlier lines omitted"},
+		{"budget of one", "x\n" + strings.Repeat("y", 100), 1, "earlier lines omitted"},
+		{"multibyte", "a\n" + strings.Repeat("ошибка сборки ", 50), 5, "earlier lines omitted"},
+		{"emoji", "a\n" + strings.Repeat("🔥", 200), 3, "earlier lines omitted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FitTokens(tt.snippet, tt.budget)
+			if !strings.Contains(got, tt.want) {
+				t.Fatalf("FitTokens() = %q, want it to contain %q", got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Fatalf("FitTokens() split a rune: %q", got)
+			}
+		})
+	}
+}
+
+func TestFitTokens_KeepsTail(t *testing.T) {
+	log := strings.Repeat("npm ERR! code ERESOLVE could not resolve dependency tree\n", 400) + "Error: build failed with exit code 1"
+	out := FitTokens(log, 300)
+	if got := EstimateTokens(out); got > 300 {
+		t.Fatalf("snippet exceeds budget: %d tokens", got)
+	}
+	if !strings.HasSuffix(out, "Error: build failed with exit code 1") || !strings.HasPrefix(out, "[") {
+		t.Fatalf("expected the tail with an omission marker, got %q", out)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// TransportConfig tunes the connection pool used to reach a provider.
+// Zero values keep the net/http defaults.
+type TransportConfig struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+	KeepAlive           time.Duration
+	// DisableHTTP2 forces HTTP/1.1 even when the server negotiates h2
+	DisableHTTP2 bool
+	// TLSConfig overrides the client TLS settings (e.g. custom roots)
+	TLSConfig *tls.Config
+	// CAFile is a PEM bundle trusted in addition to the system roots. When
+	// empty, the provider's CA file variable (see CAFileEnv) is read.
+	CAFile string
+	// CAData holds PEM certificates trusted in addition to the system
+	// roots, e.g. read from a ConfigMap
+	CAData []byte
+}
+
+// caFileEnvs name the variable holding the CA bundle of each provider, so
+// a gateway with an internal CA can be trusted without affecting others
+var caFileEnvs = map[string]string{
+	ProviderLightspeed:       "LIGHTSPEED_CA_FILE",
+	ProviderOpenAICompatible: "OPENAI_CA_FILE",
+	ProviderAzureOpenAI:      "AZURE_OPENAI_CA_FILE",
+}
+
+// CAFileEnv returns the variable read for the CA bundle of provider, or ""
+// when it has none
+func CAFileEnv(provider string) string {
+	if provider == "" {
+		provider = ProviderLightspeed
+	}
+	return caFileEnvs[provider]
+}
+
+// newHTTPClient builds the HTTP client shared by all providers
+func newHTTPClient(cfg Config) *http.Client {
+	tc := cfg.Transport
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if tc.MaxIdleConns > 0 {
+		transport.MaxIdleConns = tc.MaxIdleConns
+	}
+	if tc.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = tc.MaxIdleConnsPerHost
+	}
+	if tc.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = tc.MaxConnsPerHost
+	}
+	if tc.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = tc.IdleConnTimeout
+	}
+	if tc.KeepAlive > 0 {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: tc.KeepAlive}
+		transport.DialContext = dialer.DialContext
+	}
+
+	if tc.TLSConfig != nil {
+		transport.TLSClientConfig = tc.TLSConfig.Clone()
+	}
+	// The CA was checked by ValidateConfig; a failure here keeps the
+	// system roots
+	if pool, err := rootCAs(cfg); err == nil && pool != nil {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+	if cfg.InsecureTLS {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+
+	if tc.DisableHTTP2 {
+		// A non-nil empty map disables the automatic h2 upgrade
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	} else {
+		// Custom TLS configs disable h2 unless explicitly requested
+		transport.ForceAttemptHTTP2 = true
+	}
+
+	var rt http.RoundTripper = transport
+	if len(cfg.Headers) > 0 {
+		rt = &headerTransport{base: transport, headers: cfg.Headers}
+	}
+	return &http.Client{Timeout: cfg.Timeout, Transport: rt}
+}
+
+// rootCAs returns the system roots extended with the configured CA file and
+// data, or nil when none is configured
+func rootCAs(cfg Config) (*x509.CertPool, error) {
+	file := cfg.Transport.CAFile
+	if file == "" && CAFileEnv(cfg.Provider) != "" {
+		file = os.Getenv(CAFileEnv(cfg.Provider))
+	}
+	if file == "" && len(cfg.Transport.CAData) == 0 {
+		return nil, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if file != "" {
+		pem, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", file)
+		}
+	}
+	if len(cfg.Transport.CAData) > 0 && !pool.AppendCertsFromPEM(cfg.Transport.CAData) {
+		return nil, fmt.Errorf("no PEM certificates found in the CA data")
+	}
+	return pool, nil
+}
+
+// headerTransport adds user supplied headers to every request. Headers set
+// by the provider itself (authentication, content type) take precedence.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		if req.Header.Get(k) == "" {
+			req.Header.Set(k, v)
+		}
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNew_ProviderCAFile(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}]}`))
+	}))
+	t.Cleanup(srv.Close)
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, cert, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Config{Provider: ProviderOpenAICompatible, BaseURL: srv.URL, Model: "m"}
+	llm, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := llm.Analyze(context.Background(), "q"); err == nil {
+		t.Fatal("expected the gateway certificate to be untrusted without a CA bundle")
+	}
+
+	t.Setenv(CAFileEnv(ProviderOpenAICompatible), caFile)
+	if llm, err = New(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := llm.Analyze(context.Background(), "q"); err != nil {
+		t.Fatalf("expected the provider CA bundle to be trusted: %v", err)
+	}
+	if _, err := New(Config{Transport: TransportConfig{CAData: []byte("not a certificate")}}); err == nil {
+		t.Fatal("expected invalid CA data to be rejected")
+	}
+}
+
+func TestNewHTTPClient_Transport(t *testing.T) {
+	tc := TransportConfig{MaxIdleConns: 50, MaxIdleConnsPerHost: 8, MaxConnsPerHost: 16, IdleConnTimeout: time.Minute, DisableHTTP2: true}
+	transport := newHTTPClient(Config{Transport: tc}).Transport.(*http.Transport)
+	if transport.MaxIdleConns != 50 || transport.MaxIdleConnsPerHost != 8 || transport.MaxConnsPerHost != 16 || transport.IdleConnTimeout != time.Minute {
+		t.Errorf("pool settings not applied: %+v", transport)
+	}
+	if transport.ForceAttemptHTTP2 || transport.TLSNextProto == nil {
+		t.Error("expected HTTP/2 to be disabled")
+	}
+
+	for _, tc := range []TransportConfig{{MaxIdleConns: -1}, {MaxIdleConnsPerHost: -1}, {MaxConnsPerHost: -1}, {IdleConnTimeout: -time.Second}, {KeepAlive: -time.Second}} {
+		if err := ValidateConfig(Config{Transport: tc}); err == nil {
+			t.Errorf("expected %+v to be rejected", tc)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PricingEnv holds the default pricing, in the format read by ParsePricing
+const PricingEnv = "TKN_ASSIST_PRICING"
+
+// Usage records the tokens consumed by one query and, when pricing is
+// configured, its estimated cost
+type Usage struct {
+	InputTokens      int      `json:"input_tokens"`
+	OutputTokens     int      `json:"output_tokens"`
+	EstimatedCostUSD *float64 `json:"estimated_cost_usd,omitempty"`
+}
+
+// Pricing is the price of a model in USD per million tokens
+type Pricing struct {
+	InputPerMTok  float64
+	OutputPerMTok float64
+}
+
+// IsZero reports whether no price is configured
+func (p Pricing) IsZero() bool {
+	return p.InputPerMTok == 0 && p.OutputPerMTok == 0
+}
+
+// Cost returns the estimated cost of u in USD
+func (p Pricing) Cost(u Usage) float64 {
+	return (float64(u.InputTokens)*p.InputPerMTok + float64(u.OutputTokens)*p.OutputPerMTok) / 1e6
+}
+
+// ParsePricing parses "input=3,output=15" (USD per million tokens). Either
+// price may be omitted; an empty string means no pricing.
+func ParsePricing(s string) (Pricing, error) {
+	var p Pricing
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(field, "=")
+		price, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !ok || err != nil || price < 0 || math.IsInf(price, 0) {
+			return Pricing{}, fmt.Errorf("invalid price %q (expected input=<USD>,output=<USD> per million tokens)", field)
+		}
+		switch strings.TrimSpace(name) {
+		case "input":
+			p.InputPerMTok = price
+		case "output":
+			p.OutputPerMTok = price
+		default:
+			return Pricing{}, fmt.Errorf("unknown price %q (expected input or output)", name)
+		}
+	}
+	return p, nil
+}
+
+// responseUsage reads the token counts of a Lightspeed shaped response. It
+// returns nil when the provider reported none.
+func responseUsage(resp string) *Usage {
+	var counts struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	}
+	if err := json.Unmarshal([]byte(resp), &counts); err != nil {
+		return nil
+	}
+	if counts.InputTokens == 0 && counts.OutputTokens == 0 {
+		return nil
+	}
+	return &Usage{InputTokens: counts.InputTokens, OutputTokens: counts.OutputTokens}
+}
+
+// Price adds the estimated cost of the call to the metadata. It does
+// nothing without pricing or reported usage.
+func (r *Result) Price(p Pricing) {
+	if r.Metadata.Usage == nil || p.IsZero() {
+		return
+	}
+	cost := p.Cost(*r.Metadata.Usage)
+	r.Metadata.Usage.EstimatedCostUSD = &cost
+}
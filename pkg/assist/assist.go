@@ -0,0 +1,196 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package assist is the entry point for Go programs (dashboards, operators)
+// that want tekton-assist diagnoses without shelling out to the CLI. Its
+// API exposes types of the analysis package and is not yet covered by a
+// compatibility promise; pin a version.
+package assist
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/analysis"
+	"github.com/openshift-pipelines/tekton-assist/pkg/i18n"
+	"github.com/openshift-pipelines/tekton-assist/pkg/types"
+)
+
+// Kind is the type of Tekton run being diagnosed
+type Kind string
+
+const (
+	// KindTaskRun identifies a TaskRun
+	KindTaskRun Kind = "TaskRun"
+	// KindPipelineRun identifies a PipelineRun
+	KindPipelineRun Kind = "PipelineRun"
+)
+
+// Ref points at the run to diagnose
+type Ref struct {
+	Kind      Kind
+	Namespace string
+	Name      string
+}
+
+// Options tunes a single diagnosis
+type Options struct {
+	// Stuck diagnoses a PipelineRun that is Pending/queued rather than failed
+	Stuck bool
+	// Hint is user supplied context injected into the prompt
+	Hint string
+	// Audience tailors the explanation; empty means standard
+	Audience analysis.Audience
+	// Language is the BCP 47 tag of the answer language (e.g. "ja",
+	// "pt-BR"); empty means English
+	Language string
+	// SkipRunbooks leaves the bundled Tekton runbook sections out of log
+	// explanations
+	SkipRunbooks bool
+	// DebugPrompt reports how the prompt of a log explanation was assembled
+	DebugPrompt bool
+}
+
+// DiagnosisResult is the outcome of a diagnosis
+type DiagnosisResult struct {
+	Ref       Ref
+	Summary   string
+	Analysis  string
+	Solutions []string
+	// Structured is the validated structured analysis, or nil when the
+	// model answered in prose
+	Structured *types.Analysis
+	// Confidence estimates how reliable the diagnosis is
+	Confidence *analysis.Confidence
+	// Snippets checks the YAML snippets of the answer against the
+	// tekton.dev/v1 API
+	Snippets []analysis.SnippetCheck
+	// Group labels runs diagnosed with the same root cause by
+	// DiagnoseGrouped
+	Group string
+	// Raw is the unmodified provider response
+	Raw      string
+	Metadata analysis.Metadata
+	// PromptDebug is set when Options.DebugPrompt was requested
+	PromptDebug *analysis.PromptDebug
+}
+
+// Client runs diagnoses against a configured provider
+type Client struct {
+	llm      analysis.LLM
+	pricing  analysis.Pricing
+	patterns []analysis.Rule
+	redactor *analysis.Redactor
+}
+
+// New creates a Client for the provider described by cfg
+func New(cfg analysis.Config) (*Client, error) {
+	llm, err := analysis.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{llm: llm, pricing: cfg.Pricing, patterns: cfg.Patterns, redactor: cfg.Redactor}, nil
+}
+
+// NewWithLLM creates a Client around an existing LLM implementation
+func NewWithLLM(llm analysis.LLM) *Client {
+	return &Client{llm: llm}
+}
+
+// Diagnose explains why the referenced run failed
+func (c *Client) Diagnose(ctx context.Context, ref Ref, opts Options) (DiagnosisResult, error) {
+	if ref.Name == "" {
+		return DiagnosisResult{}, fmt.Errorf("run name is required")
+	}
+	if ref.Namespace == "" {
+		ref.Namespace = "default"
+	}
+	if err := analysis.CheckClusterAccess(c.llm.Name()); err != nil {
+		return DiagnosisResult{}, err
+	}
+
+	var query string
+	switch ref.Kind {
+	case KindTaskRun:
+		query = analysis.TaskRunQuery(ref.Name, ref.Namespace)
+	case KindPipelineRun:
+		query = analysis.PipelineRunQuery(ref.Name, ref.Namespace)
+		if opts.Stuck {
+			query = analysis.StuckPipelineRunQuery(ref.Name, ref.Namespace)
+		}
+	default:
+		return DiagnosisResult{}, fmt.Errorf("unsupported kind %q", ref.Kind)
+	}
+
+	return c.run(ctx, ref, opts.apply(query), "")
+}
+
+// Explain explains a failure from arbitrary log text
+func (c *Client) Explain(ctx context.Context, log string, opts Options) (DiagnosisResult, error) {
+	query, debug := analysis.LogPrompt{
+		Runbooks: !opts.SkipRunbooks,
+		Audience: opts.Audience,
+		Language: language(opts.Language),
+		Hint:     opts.Hint,
+		Patterns: c.patterns,
+		Redactor: c.redactor,
+	}.Build(log)
+	res, err := c.run(ctx, Ref{}, query, log)
+	if err == nil && opts.DebugPrompt {
+		res.PromptDebug = &debug
+	}
+	return res, err
+}
+
+// apply adds the audience guidance, answer language and hint to a query
+func (o Options) apply(query string) string {
+	return analysis.WithHint(analysis.WithLanguage(analysis.WithAudience(query, o.Audience), language(o.Language)), o.Hint)
+}
+
+// language returns the name of the language tagged tag for prompts
+func language(tag string) string {
+	if tag == "" {
+		return ""
+	}
+	return i18n.Name(tag)
+}
+
+// run sends query and assesses the answer against evidence, the log the
+// query was built from (empty when the provider reads the run itself)
+func (c *Client) run(ctx context.Context, ref Ref, query, evidence string) (DiagnosisResult, error) {
+	result, err := analysis.Run(ctx, c.llm, query)
+	if err != nil {
+		return DiagnosisResult{}, err
+	}
+	result.Price(c.pricing)
+	if evidence != "" {
+		result.Assess(evidence)
+	}
+	return newDiagnosis(ref, result), nil
+}
+
+func newDiagnosis(ref Ref, result *analysis.Result) DiagnosisResult {
+	answer := analysis.ParseAnswer(result.Response)
+	return DiagnosisResult{
+		Ref:        ref,
+		Summary:    answer.Summary,
+		Analysis:   answer.Analysis,
+		Solutions:  answer.Solutions,
+		Structured: result.Structured,
+		Confidence: result.Confidence,
+		Snippets:   result.Snippets,
+		Raw:        result.Response,
+		Metadata:   result.Metadata,
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assist
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/analysis"
+)
+
+// DefaultConcurrency bounds the diagnoses DiagnoseAll runs at once
+const DefaultConcurrency = 4
+
+// BatchResult is the outcome of one run of a batch. Err is set when the
+// run could not be diagnosed.
+type BatchResult struct {
+	Ref    Ref
+	Result DiagnosisResult
+	Err    error
+}
+
+// DiagnoseAll diagnoses refs with at most concurrency diagnoses in flight
+// and returns the results in the order of refs. A failed diagnosis does
+// not stop the others.
+func (c *Client) DiagnoseAll(ctx context.Context, refs []Ref, opts Options, concurrency int) []BatchResult {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	results := make([]BatchResult, len(refs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, ref := range refs {
+		wg.Add(1)
+		go func(i int, ref Ref) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i] = BatchResult{Ref: ref, Err: ctx.Err()}
+				return
+			}
+			res, err := c.Diagnose(ctx, ref, opts)
+			results[i] = BatchResult{Ref: ref, Result: res, Err: err}
+		}(i, ref)
+	}
+	wg.Wait()
+	return results
+}
+
+// DiagnoseGrouped diagnoses refs with one provider call per
+// analysis.MaxGroupedRuns runs instead of one call each, with at most
+// concurrency calls in flight, and labels runs that fail for the same root
+// cause with the same Group. It is cheaper and faster than DiagnoseAll for
+// many runs broken by one change. The returned Metadata sums the duration
+// and usage of all calls; the Metadata of each result has no usage of its
+// own.
+func (c *Client) DiagnoseGrouped(ctx context.Context, refs []Ref, opts Options, concurrency int) ([]BatchResult, analysis.Metadata) {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	results := make([]BatchResult, len(refs))
+	var total analysis.Metadata
+	if err := analysis.CheckClusterAccess(c.llm.Name()); err != nil {
+		for i, ref := range refs {
+			results[i] = BatchResult{Ref: ref, Err: err}
+		}
+		return results, total
+	}
+	sem := make(chan struct{}, concurrency)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for start := 0; start < len(refs); start += analysis.MaxGroupedRuns {
+		end := start + analysis.MaxGroupedRuns
+		if end > len(refs) {
+			end = len(refs)
+		}
+		wg.Add(1)
+		go func(start int, chunk []Ref) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				for i, ref := range chunk {
+					results[start+i] = BatchResult{Ref: ref, Err: ctx.Err()}
+				}
+				return
+			}
+			meta, ok := c.diagnoseChunk(ctx, chunk, opts, results[start:start+len(chunk)])
+			if ok {
+				mu.Lock()
+				total = addMetadata(total, meta)
+				mu.Unlock()
+			}
+		}(start, refs[start:end])
+	}
+	wg.Wait()
+	return results, total
+}
+
+// diagnoseChunk diagnoses up to analysis.MaxGroupedRuns refs in one
+// provider call and stores their results in results. It returns the
+// metadata of the call and whether the call was made.
+func (c *Client) diagnoseChunk(ctx context.Context, refs []Ref, opts Options, results []BatchResult) (analysis.Metadata, bool) {
+	chunk := append([]Ref(nil), refs...)
+	ids := make([]analysis.RunID, len(chunk))
+	for i := range chunk {
+		if chunk[i].Namespace == "" {
+			chunk[i].Namespace = "default"
+		}
+		ids[i] = analysis.RunID{Kind: string(chunk[i].Kind), Namespace: chunk[i].Namespace, Name: chunk[i].Name}
+	}
+
+	result, err := analysis.Run(ctx, c.llm, opts.apply(analysis.GroupedQuery(ids)))
+	var split []*analysis.Result
+	var groups []string
+	var meta analysis.Metadata
+	if err == nil {
+		result.Price(c.pricing)
+		meta = result.Metadata
+		split, groups, err = analysis.SplitGrouped(result, ids)
+	}
+	for i, ref := range chunk {
+		switch {
+		case err != nil:
+			results[i] = BatchResult{Ref: ref, Err: err}
+		case split[i] == nil:
+			results[i] = BatchResult{Ref: ref, Err: fmt.Errorf("the grouped answer has no diagnosis for this run")}
+		default:
+			d := newDiagnosis(ref, split[i])
+			d.Group = groups[i]
+			results[i] = BatchResult{Ref: ref, Result: d}
+		}
+	}
+	return meta, result != nil
+}
+
+// addMetadata adds the duration and usage of m to total
+func addMetadata(total, m analysis.Metadata) analysis.Metadata {
+	duration, usage := total.DurationMS+m.DurationMS, total.Usage
+	total = m
+	total.DurationMS = duration
+	if m.Usage == nil {
+		total.Usage = usage
+		return total
+	}
+	if usage == nil {
+		usage = &analysis.Usage{}
+	}
+	sum := *usage
+	sum.InputTokens += m.Usage.InputTokens
+	sum.OutputTokens += m.Usage.OutputTokens
+	if c := m.Usage.EstimatedCostUSD; c != nil {
+		cost := *c
+		if sum.EstimatedCostUSD != nil {
+			cost += *sum.EstimatedCostUSD
+		}
+		sum.EstimatedCostUSD = &cost
+	}
+	total.Usage = &sum
+	return total
+}
+
+// ParseRefs reads run references, one per line as [kind/]namespace/name
+// (kind is taskrun or pipelinerun), as JSON lines or as a JSON array of
+// {"kind", "namespace", "name"} objects. Blank lines and lines starting
+// with # are skipped. References without a kind use defaultKind; names
+// without a namespace use defaultNamespace.
+func ParseRefs(data []byte, defaultKind Kind, defaultNamespace string) ([]Ref, error) {
+	type jsonRef struct {
+		Kind      string `json:"kind"`
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+	}
+	toRef := func(r jsonRef) (Ref, error) {
+		kind := defaultKind
+		if r.Kind != "" {
+			var err error
+			if kind, err = ParseKind(r.Kind); err != nil {
+				return Ref{}, err
+			}
+		}
+		if r.Name == "" {
+			return Ref{}, fmt.Errorf("run name is required")
+		}
+		if r.Namespace == "" {
+			r.Namespace = defaultNamespace
+		}
+		return Ref{Kind: kind, Namespace: r.Namespace, Name: r.Name}, nil
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var list []jsonRef
+		if err := json.Unmarshal(trimmed, &list); err != nil {
+			return nil, fmt.Errorf("invalid JSON run list: %w", err)
+		}
+		refs := make([]Ref, 0, len(list))
+		for i, r := range list {
+			ref, err := toRef(r)
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: %w", i+1, err)
+			}
+			refs = append(refs, ref)
+		}
+		return refs, nil
+	}
+
+	var refs []Ref
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var r jsonRef
+		if strings.HasPrefix(line, "{") {
+			if err := json.Unmarshal([]byte(line), &r); err != nil {
+				return nil, fmt.Errorf("line %d: invalid JSON: %w", n, err)
+			}
+		} else {
+			parts := strings.Split(line, "/")
+			switch len(parts) {
+			case 1:
+				r.Name = parts[0]
+			case 2:
+				r.Namespace, r.Name = parts[0], parts[1]
+			case 3:
+				r.Kind, r.Namespace, r.Name = parts[0], parts[1], parts[2]
+			default:
+				return nil, fmt.Errorf("line %d: expected [kind/]namespace/name, got %q", n, line)
+			}
+		}
+		ref, err := toRef(r)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		refs = append(refs, ref)
+	}
+	return refs, scanner.Err()
+}
+
+// ParseKind accepts the kind names and short names used by kubectl and tkn
+func ParseKind(s string) (Kind, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "taskrun", "taskruns", "tr":
+		return KindTaskRun, nil
+	case "pipelinerun", "pipelineruns", "pr":
+		return KindPipelineRun, nil
+	}
+	return "", fmt.Errorf("unknown kind %q (expected taskrun or pipelinerun)", s)
+}
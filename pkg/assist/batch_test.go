@@ -0,0 +1,138 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/analysis"
+)
+
+type renamedLLM struct {
+	analysis.LLM
+	name string
+}
+
+func (r renamedLLM) Name() string { return r.name }
+
+func TestDiagnoseGrouped(t *testing.T) {
+	calls := 0
+	var prompt string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var req struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		prompt = req.Messages[len(req.Messages)-1].Content
+		answer, _ := json.Marshal(map[string]interface{}{"diagnoses": []map[string]interface{}{
+			{"index": 2, "group": "registry outage", "response": "pull failed", "root_cause": "quay.example.com returned 503.", "category": "image", "solutions": []string{"retry"}, "confidence": 0.9},
+			{"index": 1, "group": "registry outage", "response": "pull failed", "root_cause": "quay.example.com returned 503.", "category": "image", "solutions": []string{"retry"}, "confidence": 0.9},
+		}})
+		content, _ := json.Marshal(string(answer))
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":` + string(content) + `}}],"usage":{"prompt_tokens":1000000,"completion_tokens":0}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	// a provider reading the runs itself, answering through the OpenAI-compatible API
+	analysis.Register("grouped", func(cfg analysis.Config) (analysis.LLM, error) {
+		cfg.Provider = analysis.ProviderOpenAICompatible
+		llm, err := analysis.New(cfg)
+		return renamedLLM{llm, "grouped"}, err
+	})
+	client, err := New(analysis.Config{Provider: "grouped", BaseURL: srv.URL, Model: "m", Pricing: analysis.Pricing{InputPerMTok: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs := []Ref{
+		{Kind: KindPipelineRun, Namespace: "team-a", Name: "build-1"},
+		{Kind: KindPipelineRun, Namespace: "team-b", Name: "build-2"},
+		{Kind: KindTaskRun, Name: "lint-3"},
+	}
+	results, meta := client.DiagnoseGrouped(context.Background(), refs, Options{}, 1)
+	if calls != 1 || !strings.Contains(prompt, "3. TaskRun 'lint-3' in namespace 'default'") {
+		t.Fatalf("expected one call listing every run, got %d:\n%s", calls, prompt)
+	}
+	for i, r := range results[:2] {
+		if r.Err != nil || r.Result.Group != "registry outage" || r.Result.Structured == nil || r.Ref != refs[i] {
+			t.Errorf("unexpected result %d: %+v", i, r)
+		}
+	}
+	if results[2].Err == nil {
+		t.Error("expected the run missing from the answer to be reported")
+	}
+	if meta.Usage == nil || meta.Usage.EstimatedCostUSD == nil || *meta.Usage.EstimatedCostUSD != 3 {
+		t.Errorf("expected the cost of the single call, got %+v", meta.Usage)
+	}
+}
+
+// slowLLM answers grouped queries with no diagnoses after a delay and
+// records the most calls it had in flight
+type slowLLM struct {
+	mu                    sync.Mutex
+	calls, inFlight, peak int
+}
+
+func (s *slowLLM) Analyze(ctx context.Context, query string) (string, error) {
+	s.mu.Lock()
+	s.calls++
+	s.inFlight++
+	s.peak = max(s.peak, s.inFlight)
+	s.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	s.mu.Lock()
+	s.inFlight--
+	s.mu.Unlock()
+	return `{"response":"{\"diagnoses\":[]}"}`, nil
+}
+func (s *slowLLM) Name() string  { return "slow" }
+func (s *slowLLM) Model() string { return "" }
+
+func TestDiagnoseGrouped_Concurrency(t *testing.T) {
+	var refs []Ref
+	for i := 0; i < 3*analysis.MaxGroupedRuns; i++ {
+		refs = append(refs, Ref{Kind: KindTaskRun, Namespace: "ci", Name: fmt.Sprintf("build-%d", i)})
+	}
+	tests := []struct {
+		concurrency int
+		peak        int
+	}{
+		{1, 1},
+		{2, 2},
+	}
+	for _, tt := range tests {
+		llm := &slowLLM{}
+		c := &Client{llm: llm}
+		results, _ := c.DiagnoseGrouped(context.Background(), refs, Options{}, tt.concurrency)
+		if llm.calls != 3 || llm.peak != tt.peak {
+			t.Errorf("concurrency %d: got %d calls, at most %d at once", tt.concurrency, llm.calls, llm.peak)
+		}
+		for i, r := range results {
+			if r.Ref != refs[i] {
+				t.Fatalf("concurrency %d: result %d is for %+v", tt.concurrency, i, r.Ref)
+			}
+		}
+	}
+}
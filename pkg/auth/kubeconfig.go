@@ -0,0 +1,190 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Minimal kubeconfig model
+type kcUser struct {
+	Token                 string `yaml:"token"`
+	TokenFile             string `yaml:"token-file"`
+	ClientCertificateData string `yaml:"client-certificate-data"`
+	ClientKeyData         string `yaml:"client-key-data"`
+}
+type kcUserEntry struct {
+	Name string `yaml:"name"`
+	User kcUser `yaml:"user"`
+}
+type kcCluster struct {
+	Server                   string `yaml:"server"`
+	CertificateAuthority     string `yaml:"certificate-authority"`
+	CertificateAuthorityData string `yaml:"certificate-authority-data"`
+	InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+}
+type kcClusterEntry struct {
+	Name    string    `yaml:"name"`
+	Cluster kcCluster `yaml:"cluster"`
+}
+type kcContext struct {
+	Cluster   string `yaml:"cluster"`
+	User      string `yaml:"user"`
+	Namespace string `yaml:"namespace"`
+}
+type kcContextEntry struct {
+	Name    string    `yaml:"name"`
+	Context kcContext `yaml:"context"`
+}
+type kubeconfig struct {
+	CurrentContext string           `yaml:"current-context"`
+	Clusters       []kcClusterEntry `yaml:"clusters"`
+	Contexts       []kcContextEntry `yaml:"contexts"`
+	Users          []kcUserEntry    `yaml:"users"`
+}
+
+// Cluster is the API server connection described by a kubeconfig context
+type Cluster struct {
+	// Path is the kubeconfig file the cluster was read from
+	Path      string
+	Context   string
+	Server    string
+	Namespace string
+	// CAData is the PEM CA bundle of the API server, if configured
+	CAData                []byte
+	InsecureSkipTLSVerify bool
+	Token                 string
+	ClientCertData        []byte
+	ClientKeyData         []byte
+}
+
+// LoadCluster returns the cluster, user and namespace of the selected
+// context. An empty contextName selects the current context.
+func LoadCluster(kubeconfigPath, contextName string) (*Cluster, error) {
+	path := kubeconfigFile(kubeconfigPath)
+	if path == "" {
+		return nil, fmt.Errorf("no kubeconfig found")
+	}
+	cfg, err := loadKubeconfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := contextName
+	if current == "" {
+		current = cfg.CurrentContext
+	}
+	if current == "" {
+		return nil, fmt.Errorf("no current context in %s", path)
+	}
+	ctx, ok := cfg.context(current)
+	if !ok {
+		return nil, fmt.Errorf("context %q not found in %s", current, path)
+	}
+
+	c := &Cluster{Path: path, Context: current, Namespace: ctx.Namespace}
+	for _, e := range cfg.Clusters {
+		if e.Name != ctx.Cluster {
+			continue
+		}
+		c.Server = e.Cluster.Server
+		c.InsecureSkipTLSVerify = e.Cluster.InsecureSkipTLSVerify
+		switch {
+		case e.Cluster.CertificateAuthorityData != "":
+			if c.CAData, err = base64.StdEncoding.DecodeString(e.Cluster.CertificateAuthorityData); err != nil {
+				return nil, fmt.Errorf("invalid certificate-authority-data for cluster %q: %w", e.Name, err)
+			}
+		case e.Cluster.CertificateAuthority != "":
+			if c.CAData, err = os.ReadFile(e.Cluster.CertificateAuthority); err != nil {
+				return nil, fmt.Errorf("failed to read certificate-authority for cluster %q: %w", e.Name, err)
+			}
+		}
+	}
+	if c.Server == "" {
+		return nil, fmt.Errorf("cluster %q of context %q has no server", ctx.Cluster, current)
+	}
+
+	if u, ok := cfg.user(ctx.User); ok {
+		c.Token = userToken(u)
+		c.ClientCertData, _ = base64.StdEncoding.DecodeString(u.ClientCertificateData)
+		c.ClientKeyData, _ = base64.StdEncoding.DecodeString(u.ClientKeyData)
+	}
+	return c, nil
+}
+
+// kubeconfigFile returns the explicit path, the first KUBECONFIG entry or
+// ~/.kube/config
+func kubeconfigFile(path string) string {
+	if path != "" {
+		return path
+	}
+	if env := os.Getenv("KUBECONFIG"); env != "" {
+		// If multiple paths, take the first
+		return strings.Split(env, string(os.PathListSeparator))[0]
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".kube", "config")
+	}
+	return ""
+}
+
+func loadKubeconfig(path string) (*kubeconfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg kubeconfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+func (k *kubeconfig) context(name string) (kcContext, bool) {
+	for _, c := range k.Contexts {
+		if c.Name == name {
+			return c.Context, true
+		}
+	}
+	return kcContext{}, false
+}
+
+func (k *kubeconfig) user(name string) (kcUser, bool) {
+	for _, u := range k.Users {
+		if u.Name == name {
+			return u.User, true
+		}
+	}
+	return kcUser{}, false
+}
+
+func userToken(u kcUser) string {
+	if u.Token != "" {
+		return u.Token
+	}
+	if u.TokenFile != "" {
+		if b, err := os.ReadFile(u.TokenFile); err == nil {
+			return string(bytes.TrimSpace(b))
+		}
+	}
+	return ""
+}
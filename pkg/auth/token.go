@@ -0,0 +1,86 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"os"
+)
+
+// serviceAccountTokenPath is where the in-cluster service account token is mounted
+const serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// ResolveToken returns the bearer token used to reach the analysis service.
+// Sources are tried in order: explicit flag, token file, LIGHTSPEED_TOKEN,
+// the kubeconfig user of the selected context and the in-cluster service
+// account token.
+func ResolveToken(tokenFlag, tokenFile, kubeconfigPath, contextName string) string {
+	if token := resolveToken(tokenFlag, tokenFile); token != "" {
+		return token
+	}
+	if token := resolveTokenFromKubeconfig(kubeconfigPath, contextName); token != "" {
+		return token
+	}
+	return readFileIfExists(serviceAccountTokenPath)
+}
+
+func resolveToken(tokenFlag, tokenFile string) string {
+	if tokenFlag != "" {
+		return tokenFlag
+	}
+	if tokenFile != "" {
+		if b, err := os.ReadFile(tokenFile); err == nil {
+			return string(bytes.TrimSpace(b))
+		}
+	}
+	if env := os.Getenv("LIGHTSPEED_TOKEN"); env != "" {
+		return env
+	}
+	return ""
+}
+
+func readFileIfExists(path string) string {
+	if b, err := os.ReadFile(path); err == nil {
+		return string(bytes.TrimSpace(b))
+	}
+	return ""
+}
+
+// resolveTokenFromKubeconfig returns the token of the user of the selected
+// context, or "" when there is none
+func resolveTokenFromKubeconfig(kubeconfigPath, contextName string) string {
+	path := kubeconfigFile(kubeconfigPath)
+	if path == "" {
+		return ""
+	}
+	cfg, err := loadKubeconfig(path)
+	if err != nil {
+		return ""
+	}
+
+	current := contextName
+	if current == "" {
+		current = cfg.CurrentContext
+	}
+	ctx, ok := cfg.context(current)
+	if !ok || ctx.User == "" {
+		return ""
+	}
+	u, ok := cfg.user(ctx.User)
+	if !ok {
+		return ""
+	}
+	return userToken(u)
+}
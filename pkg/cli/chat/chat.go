@@ -0,0 +1,158 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/analysis"
+	"github.com/openshift-pipelines/tekton-assist/pkg/cli/options"
+	"github.com/openshift-pipelines/tekton-assist/pkg/i18n"
+	"github.com/spf13/cobra"
+)
+
+// ChatOptions holds options specific to the chat command
+type ChatOptions struct {
+	RunName   string
+	Kind      string
+	Namespace string
+	options.ProviderOptions
+}
+
+// ChatCommand creates the interactive chat command
+func ChatCommand() *cobra.Command {
+	opts := &ChatOptions{
+		Kind:            "pipelinerun",
+		ProviderOptions: options.NewProviderOptions(60 * time.Second),
+	}
+
+	chatCmd := &cobra.Command{
+		Use:   "chat",
+		Short: "Ask follow-up questions about a failed run",
+		Long: `Chat opens an interactive session about a TaskRun or PipelineRun.
+
+The session starts with a diagnosis of the run and then answers follow-up
+questions. The conversation is kept locally and replayed to the provider
+with every question, so answers stay in the context of the run.
+
+Type 'exit' or 'quit' (or press Ctrl-D) to end the session.`,
+		Example: `  # Chat about a failed PipelineRun
+  tkn-assist chat -n my-namespace --run my-pipelinerun
+
+  # Chat about a failed TaskRun
+  tkn-assist chat -n my-namespace --run my-taskrun --kind taskrun`,
+		Annotations: map[string]string{"commandType": "main"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runChat(cmd.Context(), cmd.InOrStdin(), cmd.OutOrStdout(), opts)
+		},
+	}
+
+	chatCmd.Flags().StringVar(&opts.RunName, "run", "", "Name of the TaskRun or PipelineRun to discuss")
+	chatCmd.Flags().StringVar(&opts.Kind, "kind", opts.Kind, "Kind of run. One of: pipelinerun|taskrun")
+	chatCmd.Flags().StringVarP(&opts.Namespace, "namespace", "n", "", "Kubernetes namespace")
+	opts.AddFlags(chatCmd)
+	_ = chatCmd.MarkFlagRequired("run")
+
+	return chatCmd
+}
+
+// runChat executes the interactive session
+func runChat(ctx context.Context, in io.Reader, out io.Writer, opts *ChatOptions) error {
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	var query string
+	switch strings.ToLower(opts.Kind) {
+	case "pipelinerun", "pr":
+		query = analysis.PipelineRunQuery(opts.RunName, namespace)
+	case "taskrun", "tr":
+		query = analysis.TaskRunQuery(opts.RunName, namespace)
+	default:
+		return fmt.Errorf("unsupported kind %q (expected pipelinerun or taskrun)", opts.Kind)
+	}
+
+	cfg, err := opts.Config()
+	if err != nil {
+		return err
+	}
+
+	if err := analysis.CheckClusterAccess(cfg.Provider); err != nil {
+		return err
+	}
+	llm, err := analysis.New(cfg)
+	if err != nil {
+		return err
+	}
+
+	// The initial diagnosis seeds the conversation
+	first, err := ask(ctx, llm, query)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s\n\n", first)
+	history := []analysis.Turn{{Question: query, Answer: first}}
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		question := strings.TrimSpace(scanner.Text())
+		switch question {
+		case "":
+			continue
+		case "exit", "quit":
+			return nil
+		}
+
+		answer, err := ask(ctx, llm, analysis.WithLanguage(analysis.ChatQuery(history, question), i18n.Name(i18n.Requested())))
+		if err != nil {
+			// Keep the session alive; the user can retry or rephrase
+			fmt.Fprintf(out, "%s: %v\n\n", i18n.T("error"), err)
+			continue
+		}
+		fmt.Fprintf(out, "%s\n\n", answer)
+		history = append(history, analysis.Turn{Question: question, Answer: answer})
+	}
+}
+
+// ask sends one query and flattens the answer into readable text
+func ask(ctx context.Context, llm analysis.LLM, query string) (string, error) {
+	result, err := analysis.Run(ctx, llm, query)
+	if err != nil {
+		return "", err
+	}
+
+	answer := analysis.ParseAnswer(result.Response)
+	var b strings.Builder
+	b.WriteString(answer.Summary)
+	if answer.Analysis != "" {
+		b.WriteString("\n\n" + answer.Analysis)
+	}
+	for i, s := range answer.Solutions {
+		fmt.Fprintf(&b, "\n  %d. %s", i+1, s)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
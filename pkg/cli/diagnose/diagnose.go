@@ -0,0 +1,286 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/analysis"
+	"github.com/openshift-pipelines/tekton-assist/pkg/assist"
+	"github.com/openshift-pipelines/tekton-assist/pkg/cli/options"
+	"github.com/openshift-pipelines/tekton-assist/pkg/i18n"
+	"github.com/spf13/cobra"
+)
+
+// DiagnoseOptions holds options specific to the batch diagnose command
+type DiagnoseOptions struct {
+	Batch       string
+	Kind        string
+	Namespace   string
+	Output      string
+	Concurrency int
+	Group       bool
+	Hint        string
+	Audience    string
+
+	options.ProviderOptions
+}
+
+// DiagnoseCommand creates the diagnose command for many runs at once
+func DiagnoseCommand() *cobra.Command {
+	opts := &DiagnoseOptions{
+		Kind:            "pipelinerun",
+		Output:          "markdown",
+		Concurrency:     assist.DefaultConcurrency,
+		ProviderOptions: options.NewProviderOptions(60 * time.Second),
+	}
+
+	diagnoseCmd := &cobra.Command{
+		Use:   "diagnose --batch <file>",
+		Short: "Diagnose many runs and produce a combined report",
+		Long: `Diagnose reads run references from a file and diagnoses them in parallel.
+
+Each line of the file is [kind/]namespace/name, where kind is taskrun or
+pipelinerun (default: --kind), or a JSON object with kind, namespace and
+name fields. A JSON array of such objects is accepted as well. Blank lines
+and lines starting with # are ignored.
+
+The combined report lists every run with its category, confidence and
+root cause, followed by the solutions for each run. Runs that could not be
+diagnosed are reported with their error.`,
+		Example: `  # Review last week's failures as Markdown
+  tkn-assist diagnose --batch runs.txt > review.md
+
+  # Diagnose TaskRuns with at most two requests in flight, as JSON
+  tkn-assist diagnose --batch runs.txt --kind taskrun --concurrency 2 -o json`,
+		Annotations: map[string]string{"commandType": "main"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiagnose(cmd.Context(), cmd.InOrStdin(), opts)
+		},
+	}
+
+	diagnoseCmd.Flags().StringVar(&opts.Batch, "batch", "", "File listing the runs to diagnose, one [kind/]namespace/name or JSON object per line (- reads standard input)")
+	diagnoseCmd.Flags().StringVar(&opts.Kind, "kind", opts.Kind, "Kind of the runs listed without one. One of: taskrun|pipelinerun")
+	diagnoseCmd.Flags().StringVarP(&opts.Namespace, "namespace", "n", "", "Namespace of the runs listed without one (default: default)")
+	diagnoseCmd.Flags().StringVarP(&opts.Output, "output", "o", opts.Output, "Output format (markdown, json)")
+	diagnoseCmd.Flags().IntVar(&opts.Concurrency, "concurrency", opts.Concurrency, "Maximum number of provider calls at the same time, each diagnosing one run (or one group of runs with --group)")
+	diagnoseCmd.Flags().BoolVar(&opts.Group, "group", false, fmt.Sprintf("Diagnose up to %d runs per provider call and group runs that share a root cause", analysis.MaxGroupedRuns))
+	opts.AddFlags(diagnoseCmd)
+	diagnoseCmd.Flags().StringVar(&opts.Hint, "hint", "", "Extra context for every analysis (e.g. \"we upgraded the base image yesterday\")")
+	diagnoseCmd.Flags().StringVar(&opts.Audience, "audience", string(analysis.AudienceStandard), "Tailor the explanations to the reader. One of: beginner|standard|expert")
+	_ = diagnoseCmd.MarkFlagRequired("batch")
+
+	return diagnoseCmd
+}
+
+// runDiagnose executes the batch diagnosis workflow
+func runDiagnose(ctx context.Context, stdin io.Reader, opts *DiagnoseOptions) error {
+	if opts.Output != "markdown" && opts.Output != "json" {
+		return fmt.Errorf("unknown output format %q (expected markdown or json)", opts.Output)
+	}
+	if opts.Concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	audience, err := analysis.ParseAudience(opts.Audience)
+	if err != nil {
+		return err
+	}
+	kind, err := assist.ParseKind(opts.Kind)
+	if err != nil {
+		return fmt.Errorf("invalid --kind: %w", err)
+	}
+
+	var data []byte
+	if opts.Batch == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(opts.Batch)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read --batch: %w", err)
+	}
+	refs, err := assist.ParseRefs(data, kind, opts.Namespace)
+	if err != nil {
+		return fmt.Errorf("%s: %w", opts.Batch, err)
+	}
+	if len(refs) == 0 {
+		return fmt.Errorf("%s lists no runs", opts.Batch)
+	}
+
+	cfg, err := opts.Config()
+	if err != nil {
+		return err
+	}
+	if err := analysis.CheckClusterAccess(cfg.Provider); err != nil {
+		return err
+	}
+	client, err := assist.New(cfg)
+	if err != nil {
+		return err
+	}
+
+	diagnoseOpts := assist.Options{
+		Hint:     opts.Hint,
+		Audience: audience,
+		Language: i18n.Requested(),
+	}
+	var report *Report
+	if opts.Group {
+		results, meta := client.DiagnoseGrouped(ctx, refs, diagnoseOpts, opts.Concurrency)
+		report = newReport(results)
+		if meta.Usage != nil {
+			report.EstimatedCostUSD = meta.Usage.EstimatedCostUSD
+		}
+	} else {
+		report = newReport(client.DiagnoseAll(ctx, refs, diagnoseOpts, opts.Concurrency))
+	}
+
+	if opts.Output == "json" {
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(string(b))
+	} else {
+		fmt.Print(report.Markdown())
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d runs could not be diagnosed", report.Failed, report.Total)
+	}
+	return nil
+}
+
+// Report is the combined outcome of a batch
+type Report struct {
+	Total            int         `json:"total"`
+	Diagnosed        int         `json:"diagnosed"`
+	Failed           int         `json:"failed"`
+	EstimatedCostUSD *float64    `json:"estimated_cost_usd,omitempty"`
+	Runs             []RunReport `json:"runs"`
+}
+
+// RunReport is the diagnosis of one run of a batch
+type RunReport struct {
+	Kind       assist.Kind          `json:"kind"`
+	Namespace  string               `json:"namespace"`
+	Name       string               `json:"name"`
+	Summary    string               `json:"summary,omitempty"`
+	RootCause  string               `json:"root_cause,omitempty"`
+	Category   string               `json:"category,omitempty"`
+	Group      string               `json:"group,omitempty"`
+	Confidence *analysis.Confidence `json:"confidence,omitempty"`
+	Solutions  []string             `json:"solutions,omitempty"`
+	Metadata   *analysis.Metadata   `json:"metadata,omitempty"`
+	Error      string               `json:"error,omitempty"`
+}
+
+func newReport(results []assist.BatchResult) *Report {
+	r := &Report{Total: len(results)}
+	var cost float64
+	priced := false
+	for _, res := range results {
+		run := RunReport{Kind: res.Ref.Kind, Namespace: res.Ref.Namespace, Name: res.Ref.Name}
+		if run.Namespace == "" {
+			run.Namespace = "default"
+		}
+		if res.Err != nil {
+			run.Error = res.Err.Error()
+			r.Failed++
+			r.Runs = append(r.Runs, run)
+			continue
+		}
+		d := res.Result
+		run.Summary, run.Solutions, run.Confidence, run.Group = d.Summary, d.Solutions, d.Confidence, d.Group
+		if s := d.Structured; s != nil {
+			run.RootCause, run.Category = s.RootCause, string(s.Category)
+		}
+		meta := d.Metadata
+		run.Metadata = &meta
+		if u := meta.Usage; u != nil && u.EstimatedCostUSD != nil {
+			cost += *u.EstimatedCostUSD
+			priced = true
+		}
+		r.Diagnosed++
+		r.Runs = append(r.Runs, run)
+	}
+	if priced {
+		r.EstimatedCostUSD = &cost
+	}
+	return r
+}
+
+// Markdown renders the report as an overview table followed by one
+// section per run
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", i18n.T("Failure Review"))
+	fmt.Fprintf(&b, "%s\n", i18n.Sprintf("%d runs, %d diagnosed, %d failed", r.Total, r.Diagnosed, r.Failed))
+	if r.EstimatedCostUSD != nil {
+		fmt.Fprintf(&b, "\n%s\n", i18n.Sprintf("Estimated cost: $%.4f", *r.EstimatedCostUSD))
+	}
+
+	fmt.Fprintf(&b, "\n| %s | %s | %s | %s |\n|---|---|---|---|\n", i18n.T("Run"), i18n.T("Category"), i18n.T("Confidence"), i18n.T("Root cause"))
+	for _, run := range r.Runs {
+		confidence, cause := "", run.RootCause
+		if run.Confidence != nil {
+			confidence = fmt.Sprintf("%s (%.2f)", i18n.T(run.Confidence.Level), run.Confidence.Score)
+		}
+		if cause == "" {
+			cause = run.Summary
+		}
+		if run.Error != "" {
+			cause = i18n.T("error") + ": " + run.Error
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", cell(run.title()), cell(run.Category), cell(confidence), cell(cause))
+	}
+
+	for _, run := range r.Runs {
+		fmt.Fprintf(&b, "\n## %s\n\n", run.title())
+		if run.Error != "" {
+			fmt.Fprintf(&b, "%s: %s\n", i18n.T("error"), run.Error)
+			continue
+		}
+		if run.Group != "" {
+			fmt.Fprintf(&b, "**%s** %s\n\n", i18n.T("Group:"), run.Group)
+		}
+		if run.Summary != "" {
+			fmt.Fprintf(&b, "**%s** %s\n", i18n.T("Summary:"), run.Summary)
+		}
+		if len(run.Solutions) > 0 {
+			fmt.Fprintf(&b, "\n**%s**\n\n", i18n.T("Solutions:"))
+			for i, s := range run.Solutions {
+				fmt.Fprintf(&b, "%d. %s\n", i+1, s)
+			}
+		}
+	}
+	return b.String()
+}
+
+func (r RunReport) title() string {
+	return fmt.Sprintf("%s %s/%s", r.Kind, r.Namespace, r.Name)
+}
+
+// cell makes text safe for a Markdown table cell
+func cell(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.ReplaceAll(text, "|", `\|`)
+}
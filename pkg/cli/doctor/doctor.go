@@ -0,0 +1,120 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/cli/options"
+	"github.com/openshift-pipelines/tekton-assist/pkg/doctor"
+	"github.com/openshift-pipelines/tekton-assist/pkg/i18n"
+	"github.com/spf13/cobra"
+)
+
+// DoctorOptions holds options specific to the doctor command
+type DoctorOptions struct {
+	Namespace string
+	Output    string
+	SkipQuery bool
+
+	options.ProviderOptions
+}
+
+// DoctorCommand creates the doctor command
+func DoctorCommand() *cobra.Command {
+	opts := &DoctorOptions{
+		Output:          "text",
+		ProviderOptions: options.NewProviderOptions(30 * time.Second),
+	}
+
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the tekton-assist setup and suggest fixes",
+		Long: `Doctor checks everything tekton-assist depends on and prints a pass/fail
+report with fixes:
+
+1. Kubeconfig and cluster connectivity
+2. Tekton CRDs and the served tekton.dev versions
+3. RBAC needed to read runs, pod logs and events
+4. Analysis provider configuration, reachability and authentication`,
+		Example: `  # Check the setup against the default Lightspeed service
+  tkn-assist doctor
+
+  # Check a hosted provider without spending tokens on a test query
+  tkn-assist doctor --provider gemini --skip-query`,
+		Annotations: map[string]string{"commandType": "main"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(cmd.Context(), os.Stdout, opts)
+		},
+	}
+
+	doctorCmd.Flags().StringVarP(&opts.Namespace, "namespace", "n", "", "Namespace to check RBAC in (default: context namespace)")
+	doctorCmd.Flags().StringVarP(&opts.Output, "output", "o", opts.Output, "Output format. One of: text|json")
+	doctorCmd.Flags().BoolVar(&opts.SkipQuery, "skip-query", false, "Do not send a test query to the provider")
+	opts.AddFlags(doctorCmd)
+
+	return doctorCmd
+}
+
+func runDoctor(ctx context.Context, out io.Writer, opts *DoctorOptions) error {
+	cfg, err := opts.Config()
+	if err != nil {
+		return err
+	}
+
+	report := doctor.Run(ctx, doctor.Options{
+		Kubeconfig:  opts.Kubeconfig,
+		KubeContext: opts.KubeContext,
+		Namespace:   opts.Namespace,
+		Provider:    cfg,
+		SkipQuery:   opts.SkipQuery,
+	})
+
+	switch opts.Output {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	default:
+		printReport(out, report)
+	}
+
+	if n := report.Failed(); n > 0 {
+		return fmt.Errorf("%d check(s) failed", n)
+	}
+	return nil
+}
+
+func printReport(out io.Writer, report *doctor.Report) {
+	for _, c := range report.Checks {
+		line := fmt.Sprintf("[%s] %s", strings.ToUpper(i18n.T(string(c.Status))), c.Name)
+		if c.Detail != "" {
+			line += ": " + c.Detail
+		}
+		fmt.Fprintln(out, line)
+		for _, fix := range c.Fixes {
+			fmt.Fprintf(out, "       %s: %s\n", i18n.T("fix"), fix)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package explain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/analysis"
+	"github.com/openshift-pipelines/tekton-assist/pkg/cli/options"
+	"github.com/openshift-pipelines/tekton-assist/pkg/i18n"
+	"github.com/openshift-pipelines/tekton-assist/pkg/progress"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// ExplainOptions holds options specific to the explain command
+type ExplainOptions struct {
+	File          string
+	Stdin         bool
+	MaxLines      int
+	MaxLogTokens  int
+	Output        string
+	Verbose       bool
+	Progress      string
+	Stream        bool
+	Hint          string
+	Audience      string
+	Runbooks      bool
+	DebugPrompt   bool
+	RulesFallback bool
+
+	options.ProviderOptions
+}
+
+// ExplainCommand creates the explain command for arbitrary log text
+func ExplainCommand() *cobra.Command {
+	opts := &ExplainOptions{
+		Output:          "text",
+		MaxLines:        analysis.DefaultSnippetLines,
+		MaxLogTokens:    analysis.DefaultSnippetTokens,
+		Runbooks:        true,
+		RulesFallback:   true,
+		ProviderOptions: options.NewProviderOptions(30 * time.Second),
+	}
+
+	explainCmd := &cobra.Command{
+		Use:   "explain",
+		Short: "Explain a failure from pasted log text",
+		Long: `Explain analyzes arbitrary log text without needing a cluster resource.
+
+The command will:
+1. Read the log from a file (-f) or standard input (--stdin)
+2. Extract the lines most likely to contain the failure
+3. Send the snippet to the analysis provider
+4. Display actionable recommendations
+
+This is useful for logs copied from other CI systems or from runs that
+no longer exist in the cluster.`,
+		Example: `  # Explain a log file
+  tkn-assist explain -f build.log
+
+  # Explain a log piped from another command
+  kubectl logs my-pod -c step-build | tkn-assist explain --stdin`,
+		Annotations: map[string]string{"commandType": "main"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExplain(cmd.Context(), cmd.InOrStdin(), opts)
+		},
+	}
+
+	explainCmd.Flags().StringVarP(&opts.File, "file", "f", "", "Path to a log file to explain")
+	explainCmd.Flags().BoolVar(&opts.Stdin, "stdin", false, "Read the log from standard input")
+	explainCmd.Flags().IntVar(&opts.MaxLines, "max-lines", opts.MaxLines, "Maximum number of log lines sent for analysis")
+	explainCmd.Flags().IntVar(&opts.MaxLogTokens, "max-log-tokens", opts.MaxLogTokens, fmt.Sprintf("Estimated token budget of the log sent for analysis, at least %d; earlier lines are dropped to fit", analysis.MinSnippetTokens))
+	explainCmd.Flags().StringVarP(&opts.Output, "output", "o", opts.Output, "Output format (text, json, yaml)")
+	explainCmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Verbose output")
+	opts.AddFlags(explainCmd)
+	explainCmd.Flags().StringVar(&opts.Hint, "hint", "", "Extra context for the analysis (e.g. \"we upgraded the base image yesterday\")")
+	explainCmd.Flags().StringVar(&opts.Audience, "audience", string(analysis.AudienceStandard), "Tailor the explanation to the reader. One of: beginner|standard|expert")
+	explainCmd.Flags().BoolVar(&opts.Runbooks, "runbooks", opts.Runbooks, "Add matching sections of the bundled Tekton runbooks to the prompt")
+	explainCmd.Flags().BoolVar(&opts.RulesFallback, "rules-fallback", opts.RulesFallback, "Answer from the offline rules for common failures when the provider fails")
+	explainCmd.Flags().BoolVar(&opts.DebugPrompt, "debug-prompt", false, "Report which parts of the log were included in the prompt and why the rest was left out")
+	explainCmd.Flags().StringVar(&opts.Progress, "progress", "none", "Emit progress events to stderr. One of: none|ndjson")
+	explainCmd.Flags().BoolVar(&opts.Stream, "stream", false, "Print the analysis as it is generated (text output only)")
+	explainCmd.MarkFlagsMutuallyExclusive("file", "stdin")
+	explainCmd.MarkFlagsOneRequired("file", "stdin")
+
+	return explainCmd
+}
+
+// runExplain executes the explain workflow
+func runExplain(ctx context.Context, stdin io.Reader, opts *ExplainOptions) error {
+	reporter, err := progress.New(opts.Progress, os.Stderr)
+	if err != nil {
+		return err
+	}
+	audience, err := analysis.ParseAudience(opts.Audience)
+	if err != nil {
+		return err
+	}
+	if opts.MaxLogTokens < analysis.MinSnippetTokens {
+		return fmt.Errorf("--max-log-tokens must be at least %d", analysis.MinSnippetTokens)
+	}
+
+	done := reporter.Start(progress.StageReadingLog)
+	raw, err := readLog(stdin, opts)
+	done(err)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(raw) == "" {
+		return fmt.Errorf("log is empty")
+	}
+
+	done = reporter.Start(progress.StageResolvingCredential)
+	cfg, err := opts.Config()
+	done(err)
+	if err != nil {
+		return err
+	}
+
+	query, debug := analysis.LogPrompt{
+		MaxLines:  opts.MaxLines,
+		MaxTokens: opts.MaxLogTokens,
+		Runbooks:  opts.Runbooks,
+		Audience:  audience,
+		Language:  i18n.Name(i18n.Requested()),
+		Hint:      opts.Hint,
+		Patterns:  cfg.Patterns,
+		Redactor:  cfg.Redactor,
+	}.Build(raw)
+	if opts.Verbose {
+		fmt.Printf("Query: %s\n", query)
+	}
+
+	llm, err := analysis.New(cfg)
+	if err != nil {
+		return err
+	}
+
+	done = reporter.Start(progress.StageCallingLLM)
+	var result *analysis.Result
+	streamed := false
+	if opts.Stream && opts.Output == "text" {
+		result, streamed, err = analysis.Stream(ctx, llm, query, os.Stdout)
+	} else {
+		result, err = analysis.Run(ctx, llm, query)
+	}
+	done(err)
+	if err != nil && !streamed && opts.RulesFallback && llm.Name() != analysis.ProviderRules {
+		if res, rerr := analysis.Run(ctx, analysis.NewRulesLLM(cfg.Patterns...), query); rerr == nil {
+			fmt.Fprintf(os.Stderr, "WARNING: %s failed, showing the offline rules diagnosis instead: %v\n", llm.Name(), err)
+			result, err = res, nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if streamed {
+		fmt.Println()
+		return nil
+	}
+	result.Price(cfg.Pricing)
+	result.Assess(raw)
+	if opts.DebugPrompt {
+		result.Debug = &debug
+	}
+
+	done = reporter.Start(progress.StageRendering)
+	err = formatOutput(result.JSON(), opts.Output)
+	done(err)
+	return err
+}
+
+// readLog returns the log text from the configured source
+func readLog(stdin io.Reader, opts *ExplainOptions) (string, error) {
+	if opts.Stdin {
+		b, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read standard input: %w", err)
+		}
+		return string(b), nil
+	}
+	b, err := os.ReadFile(opts.File)
+	if err != nil {
+		return "", fmt.Errorf("failed to read log file: %w", err)
+	}
+	return string(b), nil
+}
+
+// formatOutput formats the API response according to the specified output format
+func formatOutput(response, format string) error {
+	var data interface{}
+	if err := json.Unmarshal([]byte(response), &data); err != nil {
+		// If it's not valid JSON, print as-is
+		fmt.Println(response)
+		return nil
+	}
+
+	switch format {
+	case "json":
+		b, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(string(b))
+	case "yaml":
+		b, err := yaml.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("failed to convert to YAML: %w", err)
+		}
+		fmt.Print(string(b))
+	default:
+		if _, ok := data.(map[string]interface{}); !ok {
+			fmt.Println(response)
+			return nil
+		}
+		displayText(response)
+	}
+	return nil
+}
+
+// displayText prints the summary, analysis and solutions of a response
+func displayText(response string) {
+	fmt.Println(i18n.Heading("Log Explanation"))
+	fmt.Println()
+
+	var extra struct {
+		Confidence *analysis.Confidence    `json:"confidence_assessment"`
+		Debug      *analysis.PromptDebug   `json:"prompt_debug"`
+		Snippets   []analysis.SnippetCheck `json:"yaml_snippets"`
+	}
+	_ = json.Unmarshal([]byte(response), &extra)
+	if c := extra.Confidence; c != nil {
+		fmt.Printf("%s\n\n", i18n.Sprintf("Confidence: %s (%.2f, %s)", i18n.T(c.Level), c.Score, i18n.T(c.Source)))
+	}
+
+	answer := analysis.ParseAnswer(response)
+	if answer.Summary != "" {
+		fmt.Printf("%s\n%s\n\n", i18n.T("Summary:"), answer.Summary)
+	}
+	if answer.Analysis != "" {
+		fmt.Printf("%s\n%s\n\n", i18n.T("Analysis & Suggested Remediation:"), answer.Analysis)
+	}
+	if len(answer.Solutions) > 0 {
+		fmt.Println(i18n.T("Solutions:"))
+		for i, s := range answer.Solutions {
+			fmt.Printf("  %d. %s\n", i+1, s)
+		}
+		fmt.Println()
+	}
+	warned := false
+	for _, c := range extra.Snippets {
+		if !c.Valid {
+			fmt.Printf("⚠️  %s\n", i18n.Sprintf("YAML snippet %d is not valid: %s", c.Index, strings.Join(c.Problems, "; ")))
+			warned = true
+		}
+	}
+	if warned {
+		fmt.Println()
+	}
+	if extra.Debug != nil {
+		displayPromptDebug(extra.Debug)
+	}
+}
+
+// displayPromptDebug prints what the prompt included and left out
+func displayPromptDebug(d *analysis.PromptDebug) {
+	fmt.Println(i18n.T("Prompt:"))
+	fmt.Printf("  %s\n", i18n.Sprintf("%d of %d log lines included, %d estimated tokens (budget %d)", d.IncludedLines, d.LogLines, d.LogTokens, d.TokenBudget))
+	for _, e := range d.Excluded {
+		fmt.Printf("  - %s: %d, %s\n", e.Reason, e.Lines, e.Detail)
+	}
+	for _, r := range d.Runbooks {
+		fmt.Printf("  + %s\n", r)
+	}
+	fmt.Printf("  %s\n\n", i18n.Sprintf("audience %s, hint %t, %d estimated prompt tokens", d.Audience, d.Hint, d.PromptTokens))
+}
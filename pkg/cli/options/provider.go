@@ -0,0 +1,351 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/analysis"
+	"github.com/openshift-pipelines/tekton-assist/pkg/auth"
+	"github.com/openshift-pipelines/tekton-assist/pkg/kube"
+	"github.com/spf13/cobra"
+)
+
+// ProviderOptions holds the connection flags shared by every command that
+// calls an analysis provider
+type ProviderOptions struct {
+	Kubeconfig            string
+	KubeContext           string
+	LightspeedURL         string
+	BearerToken           string
+	TokenFile             string
+	InsecureTLS           bool
+	Timeout               time.Duration
+	Provider              string
+	Model                 string
+	Headers               []string
+	CAFile                string
+	CAConfigMap           string
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+	MaxConnsPerHost       int
+	IdleConnTimeout       time.Duration
+	KeepAlive             time.Duration
+	DisableHTTP2          bool
+	CompletionsPath       string
+	CacheTTL              time.Duration
+	CacheDir              string
+	MaxTokens             int
+	Temperature           float64
+	TopP                  float64
+	Stop                  []string
+	ReasoningEffort       string
+	APIVersion            string
+	MaxCompletionTokens   int
+	SafetyThreshold       string
+	Pricing               string
+	Patterns              string
+	PatternsConfigMap     string
+	Redact                bool
+	RedactPatterns        []string
+	SafeMode              bool
+	SystemPrompt          string
+	SystemPromptFile      string
+	SystemPromptConfigMap string
+	PromptVars            []string
+
+	// changed reports whether a flag was set, so unset sampling flags keep
+	// the provider defaults
+	changed func(name string) bool
+}
+
+// NewProviderOptions returns ProviderOptions with the default provider and
+// the given request timeout
+func NewProviderOptions(timeout time.Duration) ProviderOptions {
+	return ProviderOptions{
+		Timeout:  timeout,
+		Provider: analysis.ProviderLightspeed,
+		Redact:   true,
+	}
+}
+
+// AddFlags registers the provider flags on cmd. Current field values are
+// used as flag defaults.
+func (o *ProviderOptions) AddFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "Path to kubeconfig file")
+	flags.StringVar(&o.KubeContext, "context", o.KubeContext, "Kubernetes context to use")
+	flags.StringVar(&o.LightspeedURL, "lightspeed-url", o.LightspeedURL, "Lightspeed service base URL (default: https://localhost:8443); for other providers, overrides the provider endpoint")
+	flags.StringVar(&o.BearerToken, "token", o.BearerToken, "Bearer token for Lightspeed service (or set LIGHTSPEED_TOKEN)")
+	flags.StringVar(&o.TokenFile, "token-file", o.TokenFile, "Path to a file containing the bearer token")
+	flags.BoolVarP(&o.InsecureTLS, "insecure-skip-tls-verify", "k", o.InsecureTLS, "Skip TLS certificate verification (insecure)")
+	flags.DurationVar(&o.Timeout, "timeout", o.Timeout, "Timeout for API requests")
+	flags.StringVar(&o.Provider, "provider", o.Provider, "Analysis provider. One of: "+strings.Join(analysis.Providers(), "|"))
+	flags.StringVar(&o.Model, "model", o.Model, "Model to request from the provider (default: provider default)")
+	flags.StringArrayVar(&o.Headers, "header", o.Headers, "Extra HTTP header sent to the provider, as Name=Value (repeatable)")
+	flags.StringVar(&o.CAFile, "ca-file", o.CAFile, "PEM bundle of additional CAs trusted for the provider endpoint (default: the provider's *_CA_FILE variable)")
+	flags.StringVar(&o.CAConfigMap, "ca-configmap", o.CAConfigMap, "ConfigMap holding additional CAs trusted for the provider endpoint, as [namespace/]name[:key] (default key: "+DefaultCAConfigMapKey+")")
+	flags.IntVar(&o.MaxIdleConns, "max-idle-conns", o.MaxIdleConns, "Maximum idle connections kept open to providers (default: 100)")
+	flags.IntVar(&o.MaxIdleConnsPerHost, "max-idle-conns-per-host", o.MaxIdleConnsPerHost, "Maximum idle connections kept open to the provider endpoint; raise it with --concurrency (default: 2)")
+	flags.IntVar(&o.MaxConnsPerHost, "max-conns-per-host", o.MaxConnsPerHost, "Maximum connections to the provider endpoint (default: unlimited)")
+	flags.DurationVar(&o.IdleConnTimeout, "idle-conn-timeout", o.IdleConnTimeout, "How long an idle provider connection is kept open (default: 90s)")
+	flags.DurationVar(&o.KeepAlive, "keep-alive", o.KeepAlive, "TCP keep-alive period of provider connections (default: 30s)")
+	flags.BoolVar(&o.DisableHTTP2, "disable-http2", o.DisableHTTP2, "Talk HTTP/1.1 to the provider even when it offers HTTP/2, e.g. behind a proxy that breaks h2")
+	flags.IntVar(&o.MaxTokens, "max-tokens", o.MaxTokens, "Maximum length of the answer in tokens (default: provider default)")
+	flags.Float64Var(&o.Temperature, "temperature", o.Temperature, "Sampling temperature; lower is more deterministic. Between 0 and 2, or 0 and 1 for anthropic (default: provider default)")
+	flags.Float64Var(&o.TopP, "top-p", o.TopP, "Nucleus sampling probability mass, in (0, 1] (default: provider default)")
+	flags.StringArrayVar(&o.Stop, "stop", o.Stop, "Stop sequence ending the answer (repeatable)")
+	flags.StringVar(&o.ReasoningEffort, "reasoning-effort", o.ReasoningEffort, "Reasoning effort of reasoning models such as o4-mini, for azure-openai and openai-compatible. One of: minimal|low|medium|high (default: provider default)")
+	flags.IntVar(&o.MaxCompletionTokens, "max-completion-tokens", o.MaxCompletionTokens, "Maximum length of reasoning and answer together in tokens, for reasoning models; use instead of --max-tokens (default: provider default)")
+	flags.StringVar(&o.SafetyThreshold, "safety-threshold", o.SafetyThreshold, "Gemini safety threshold applied to all harm categories. One of: "+strings.Join(analysis.GeminiSafetyThresholds, "|")+" (default: provider default)")
+	o.changed = flags.Changed
+	flags.DurationVar(&o.CacheTTL, "cache-ttl", o.CacheTTL, "Reuse the analysis of an identical failure for this long (0 disables the cache)")
+	flags.StringVar(&o.CacheDir, "cache-dir", analysis.DefaultCacheDir(), "Directory of the analysis cache")
+	flags.StringVar(&o.Pricing, "pricing", os.Getenv(analysis.PricingEnv), "Model price in USD per million tokens, as input=<price>,output=<price>, used to estimate the cost of each analysis (or set "+analysis.PricingEnv+")")
+	flags.StringVar(&o.Patterns, "patterns", os.Getenv(analysis.PatternsEnv), "YAML catalog of known failure patterns consulted with the provider (or set "+analysis.PatternsEnv+")")
+	flags.StringVar(&o.PatternsConfigMap, "patterns-configmap", o.PatternsConfigMap, "ConfigMap holding the pattern catalog, as [namespace/]name[:key] (default key: "+DefaultPatternsConfigMapKey+")")
+	flags.BoolVar(&o.Redact, "redact", o.Redact, "Mask credentials and personal data (tokens, keys, passwords, emails) before anything is sent to the provider")
+	flags.StringArrayVar(&o.RedactPatterns, "redact-pattern", o.RedactPatterns, "Extra regular expression to mask before sending; a group named secret masks only that group (repeatable)")
+	flags.BoolVar(&o.SafeMode, "safe-mode", envBool(analysis.SafeModeEnv), "Replace namespace, run, image and host names with tokens such as ns-1 and image-A before querying an external provider, and restore them in the answer (or set "+analysis.SafeModeEnv+")")
+	flags.StringVar(&o.SystemPrompt, "system-prompt", os.Getenv(analysis.SystemPromptEnv), "System message sent to the provider; may use {{.Cluster}}, {{.Context}} and {{.Vars.<name>}} (or set "+analysis.SystemPromptEnv+")")
+	flags.StringVar(&o.SystemPromptFile, "system-prompt-file", o.SystemPromptFile, "File holding the system prompt template; takes precedence over --system-prompt")
+	flags.StringVar(&o.SystemPromptConfigMap, "system-prompt-configmap", o.SystemPromptConfigMap, "ConfigMap holding the system prompt template, as [namespace/]name[:key] (default key: "+DefaultSystemPromptConfigMapKey+"); takes precedence over --system-prompt")
+	flags.StringArrayVar(&o.PromptVars, "prompt-var", o.PromptVars, "Variable of the system prompt template, as name=value, used as {{.Vars.name}} (repeatable)")
+	flags.StringVar(&o.APIVersion, "api-version", os.Getenv(analysis.AzureOpenAIAPIVersionEnv), "Data-plane api-version of azure-openai (default: "+analysis.DefaultAzureAPIVersion+", or set "+analysis.AzureOpenAIAPIVersionEnv+")")
+	flags.StringVar(&o.CompletionsPath, "completions-path", o.CompletionsPath, "Chat completions path for openai-compatible servers (default: /v1/chat/completions)")
+}
+
+// Config resolves credentials and returns the analysis.Config described by
+// the flags
+func (o *ProviderOptions) Config() (analysis.Config, error) {
+	headers := map[string]string{}
+	for _, h := range o.Headers {
+		name, value, ok := strings.Cut(h, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return analysis.Config{}, fmt.Errorf("invalid --header %q, expected Name=Value", h)
+		}
+		headers[strings.TrimSpace(name)] = value
+	}
+	pricing, err := analysis.ParsePricing(o.Pricing)
+	if err != nil {
+		return analysis.Config{}, fmt.Errorf("invalid --pricing: %w", err)
+	}
+
+	caData, err := o.configMapValue("--ca-configmap", o.CAConfigMap, DefaultCAConfigMapKey)
+	if err != nil {
+		return analysis.Config{}, err
+	}
+	patterns, err := o.loadPatterns()
+	if err != nil {
+		return analysis.Config{}, err
+	}
+	systemPrompt, err := o.systemPrompt()
+	if err != nil {
+		return analysis.Config{}, err
+	}
+	redactor := analysis.NoRedaction
+	if o.Redact {
+		if redactor, err = analysis.NewRedactor(o.RedactPatterns...); err != nil {
+			return analysis.Config{}, fmt.Errorf("invalid --redact-pattern: %w", err)
+		}
+	}
+	if o.InsecureTLS {
+		fmt.Fprintln(os.Stderr, "WARNING: TLS certificate verification is disabled (--insecure-skip-tls-verify). "+
+			"The provider endpoint is not authenticated, and credentials and logs can be intercepted.")
+	}
+
+	cfg := analysis.Config{
+		Provider:            o.Provider,
+		Model:               o.Model,
+		BaseURL:             o.LightspeedURL,
+		Token:               auth.ResolveToken(o.BearerToken, o.TokenFile, o.Kubeconfig, o.KubeContext),
+		InsecureTLS:         o.InsecureTLS,
+		Timeout:             o.Timeout,
+		Headers:             headers,
+		APIVersion:          o.APIVersion,
+		CompletionsPath:     o.CompletionsPath,
+		CacheTTL:            o.CacheTTL,
+		CacheDir:            o.CacheDir,
+		MaxTokens:           o.MaxTokens,
+		Stop:                o.Stop,
+		ReasoningEffort:     o.ReasoningEffort,
+		MaxCompletionTokens: o.MaxCompletionTokens,
+		SafetyThreshold:     o.SafetyThreshold,
+		Pricing:             pricing,
+		Patterns:            patterns,
+		Redactor:            redactor,
+		SafeMode:            o.SafeMode,
+		SystemPrompt:        systemPrompt,
+		Transport: analysis.TransportConfig{
+			MaxIdleConns:        o.MaxIdleConns,
+			MaxIdleConnsPerHost: o.MaxIdleConnsPerHost,
+			MaxConnsPerHost:     o.MaxConnsPerHost,
+			IdleConnTimeout:     o.IdleConnTimeout,
+			KeepAlive:           o.KeepAlive,
+			DisableHTTP2:        o.DisableHTTP2,
+			CAFile:              o.CAFile,
+			CAData:              caData,
+		},
+	}
+	if o.isSet("temperature") {
+		t := o.Temperature
+		cfg.Temperature = &t
+	}
+	if o.isSet("top-p") {
+		p := o.TopP
+		cfg.TopP = &p
+	}
+	return cfg, nil
+}
+
+// envBool reports whether the environment variable env is set to a true
+// value
+func envBool(env string) bool {
+	v, _ := strconv.ParseBool(os.Getenv(env))
+	return v
+}
+
+func (o *ProviderOptions) isSet(flag string) bool {
+	return o.changed != nil && o.changed(flag)
+}
+
+// DefaultCAConfigMapKey is the key of the trusted CA bundle that OpenShift
+// injects into ConfigMaps labeled config.openshift.io/inject-trusted-cabundle
+const DefaultCAConfigMapKey = "ca-bundle.crt"
+
+// DefaultPatternsConfigMapKey is the ConfigMap key read by
+// --patterns-configmap
+const DefaultPatternsConfigMapKey = "patterns.yaml"
+
+// loadPatterns reads the pattern catalogs of --patterns and
+// --patterns-configmap
+func (o *ProviderOptions) loadPatterns() ([]analysis.Rule, error) {
+	var rules []analysis.Rule
+	if o.Patterns != "" {
+		data, err := os.ReadFile(o.Patterns)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read --patterns: %w", err)
+		}
+		if rules, err = analysis.LoadPatterns(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", o.Patterns, err)
+		}
+	}
+	data, err := o.configMapValue("--patterns-configmap", o.PatternsConfigMap, DefaultPatternsConfigMapKey)
+	if err != nil || data == nil {
+		return rules, err
+	}
+	more, err := analysis.LoadPatterns(data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s: %w", o.PatternsConfigMap, err)
+	}
+	return append(rules, more...), nil
+}
+
+// DefaultSystemPromptConfigMapKey is the ConfigMap key read by
+// --system-prompt-configmap
+const DefaultSystemPromptConfigMapKey = "system-prompt"
+
+// systemPrompt returns the rendered system prompt of --system-prompt-file,
+// --system-prompt-configmap or --system-prompt, in order of precedence
+func (o *ProviderOptions) systemPrompt() (string, error) {
+	text := o.SystemPrompt
+	switch {
+	case o.SystemPromptFile != "":
+		data, err := os.ReadFile(o.SystemPromptFile)
+		if err != nil {
+			return "", fmt.Errorf("cannot read --system-prompt-file: %w", err)
+		}
+		text = string(data)
+	case o.SystemPromptConfigMap != "":
+		data, err := o.configMapValue("--system-prompt-configmap", o.SystemPromptConfigMap, DefaultSystemPromptConfigMapKey)
+		if err != nil {
+			return "", err
+		}
+		text = string(data)
+	}
+	text = strings.TrimSpace(text)
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	data := analysis.SystemPromptData{Vars: map[string]string{}}
+	for _, v := range o.PromptVars {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return "", fmt.Errorf("invalid --prompt-var %q, expected name=value", v)
+		}
+		data.Vars[strings.TrimSpace(name)] = value
+	}
+	// Without a usable kubeconfig the cluster variables are left empty
+	if cluster, err := auth.LoadCluster(o.Kubeconfig, o.KubeContext); err == nil {
+		data.Context = cluster.Context
+		if u, err := url.Parse(cluster.Server); err == nil {
+			data.Cluster = u.Hostname()
+		}
+	}
+	return analysis.RenderSystemPrompt(text, data)
+}
+
+// configMapValue reads a key of the ConfigMap referenced by a flag as
+// [namespace/]name[:key] through the kubeconfig cluster. An empty ref
+// returns nil.
+func (o *ProviderOptions) configMapValue(flag, ref, defaultKey string) ([]byte, error) {
+	if ref == "" {
+		return nil, nil
+	}
+	nameRef, key, _ := strings.Cut(ref, ":")
+	if key == "" {
+		key = defaultKey
+	}
+	namespace, name, ok := strings.Cut(nameRef, "/")
+	if !ok {
+		namespace, name = "", nameRef
+	}
+	if name == "" {
+		return nil, fmt.Errorf("invalid %s %q, expected [namespace/]name[:key]", flag, ref)
+	}
+
+	cluster, err := auth.LoadCluster(o.Kubeconfig, o.KubeContext)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", flag, err)
+	}
+	if namespace == "" {
+		namespace = cluster.Namespace
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+	client, err := kube.NewClient(cluster, o.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", flag, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), client.Timeout())
+	defer cancel()
+	data, err := client.ConfigMap(ctx, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read ConfigMap %s/%s: %w", namespace, name, err)
+	}
+	value, ok := data[key]
+	if !ok {
+		return nil, fmt.Errorf("ConfigMap %s/%s has no key %q", namespace, name, key)
+	}
+	return []byte(value), nil
+}
@@ -16,18 +16,16 @@ package pipelinerun
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
-	"bytes"
-
+	"github.com/openshift-pipelines/tekton-assist/pkg/analysis"
+	"github.com/openshift-pipelines/tekton-assist/pkg/cli/options"
+	"github.com/openshift-pipelines/tekton-assist/pkg/i18n"
+	"github.com/openshift-pipelines/tekton-assist/pkg/progress"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 )
@@ -38,20 +36,20 @@ type DiagnoseOptions struct {
 	Output          string
 	Namespace       string
 	Verbose         bool
-	Kubeconfig      string
-	KubeContext     string
-	LightspeedURL   string
-	BearerToken     string
-	TokenFile       string
-	InsecureTLS     bool
-	Timeout         time.Duration
+	Stuck           bool
+	Progress        string
+	Stream          bool
+	Hint            string
+	Audience        string
+
+	options.ProviderOptions
 }
 
 // DiagnoseCommand creates the diagnose command for PipelineRuns
 func DiagnoseCommand() *cobra.Command {
 	opts := &DiagnoseOptions{
-		Output:  "text",
-		Timeout: 30 * time.Second,
+		Output:          "text",
+		ProviderOptions: options.NewProviderOptions(30 * time.Second),
 	}
 
 	diagnoseCmd := &cobra.Command{
@@ -76,6 +74,9 @@ The analysis helps identify root causes and provides actionable remediation step
   # Diagnose in a specific namespace
   tkn-assist pipelinerun diagnose my-failed-pipelinerun --namespace my-namespace
 
+  # Explain why a PipelineRun is still Pending
+  tkn-assist pipelinerun diagnose my-queued-pipelinerun --stuck
+
   # Use a custom API server URL
   tkn-assist pipelinerun diagnose my-failed-pipelinerun --url http://custom-server:8080`,
 		Annotations: map[string]string{"commandType": "main"},
@@ -90,23 +91,31 @@ The analysis helps identify root causes and provides actionable remediation step
 	diagnoseCmd.Flags().StringVarP(&opts.Output, "output", "o", opts.Output, "Output format. One of: text|json|yaml")
 	diagnoseCmd.Flags().StringVarP(&opts.Namespace, "namespace", "n", "", "Kubernetes namespace")
 	diagnoseCmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Verbose output")
-	diagnoseCmd.Flags().StringVar(&opts.Kubeconfig, "kubeconfig", "", "Path to kubeconfig file")
-	diagnoseCmd.Flags().StringVar(&opts.KubeContext, "context", "", "Kubernetes context to use")
-	diagnoseCmd.Flags().StringVar(&opts.LightspeedURL, "lightspeed-url", "", "Lightspeed service base URL (default: https://localhost:8443)")
-	diagnoseCmd.Flags().StringVar(&opts.BearerToken, "token", "", "Bearer token for Lightspeed service (or set LIGHTSPEED_TOKEN)")
-	diagnoseCmd.Flags().StringVar(&opts.TokenFile, "token-file", "", "Path to a file containing the bearer token")
-	diagnoseCmd.Flags().BoolVarP(&opts.InsecureTLS, "insecure-skip-tls-verify", "k", false, "Skip TLS certificate verification (insecure)")
-	diagnoseCmd.Flags().DurationVar(&opts.Timeout, "timeout", opts.Timeout, "Timeout for API requests")
+	opts.AddFlags(diagnoseCmd)
+	diagnoseCmd.Flags().BoolVar(&opts.Stuck, "stuck", false, "Diagnose a PipelineRun stuck in Pending/queued instead of a failed one")
+	diagnoseCmd.Flags().StringVar(&opts.Hint, "hint", "", "Extra context for the analysis (e.g. \"we upgraded the base image yesterday\")")
+	diagnoseCmd.Flags().StringVar(&opts.Audience, "audience", string(analysis.AudienceStandard), "Tailor the explanation to the reader. One of: beginner|standard|expert")
+	diagnoseCmd.Flags().StringVar(&opts.Progress, "progress", "none", "Emit progress events to stderr. One of: none|ndjson")
+	diagnoseCmd.Flags().BoolVar(&opts.Stream, "stream", false, "Print the analysis as it is generated (text output only)")
 
 	return diagnoseCmd
 }
 
 // runDiagnose executes the diagnosis workflow
 func runDiagnose(ctx context.Context, opts *DiagnoseOptions) error {
+	reporter, err := progress.New(opts.Progress, os.Stderr)
+	if err != nil {
+		return err
+	}
+	audience, err := analysis.ParseAudience(opts.Audience)
+	if err != nil {
+		return err
+	}
+
 	if opts.Verbose {
 		fmt.Printf("Diagnosing PipelineRun: %s\n", opts.PipelineRunName)
 		if opts.Namespace != "" {
-			fmt.Printf("Namespace: %s\n", opts.Namespace)
+			fmt.Printf("%s: %s\n", i18n.T("Namespace"), opts.Namespace)
 		}
 		fmt.Printf("Output format: %s\n", opts.Output)
 		if opts.LightspeedURL != "" {
@@ -117,10 +126,10 @@ func runDiagnose(ctx context.Context, opts *DiagnoseOptions) error {
 	// Determine the Lightspeed base URL
 	baseURL := opts.LightspeedURL
 	if baseURL == "" {
-		baseURL = "https://localhost:8443"
+		baseURL = analysis.DefaultLightspeedURL
 	}
 
-	if opts.Verbose {
+	if opts.Verbose && opts.Provider == analysis.ProviderLightspeed {
 		fmt.Printf("Connecting to Lightspeed at: %s\n", baseURL)
 	}
 
@@ -133,75 +142,60 @@ func runDiagnose(ctx context.Context, opts *DiagnoseOptions) error {
 		}
 	}
 
-	// Build query payload (chat-style phrasing + ask for solutions + JSON shape)
-	query := fmt.Sprintf(
-		"Why is my Tekton PipelineRun '%s' failing in namespace '%s'? "+
-			"Provide a brief summary, a clear root-cause analysis, and 3-5 actionable solutions. "+
-			"If possible, respond as a JSON object with fields: response (string), analysis (string), solutions (array of strings).",
-		opts.PipelineRunName, namespace,
-	)
+	query := analysis.PipelineRunQuery(opts.PipelineRunName, namespace)
+	if opts.Stuck {
+		query = analysis.StuckPipelineRunQuery(opts.PipelineRunName, namespace)
+	}
+	query = analysis.WithAudience(query, audience)
+	query = analysis.WithLanguage(query, i18n.Name(i18n.Requested()))
+	query = analysis.WithHint(query, opts.Hint)
 	if opts.Verbose {
 		fmt.Printf("Query: %s\n", query)
 	}
 
-	payload := map[string]interface{}{
-		"query": query,
-	}
-	bodyBytes, err := json.Marshal(payload)
+	// Resolve token
+	done := reporter.Start(progress.StageResolvingCredential)
+	cfg, err := opts.Config()
+	done(err)
 	if err != nil {
-		return fmt.Errorf("failed to marshal request body: %w", err)
+		return err
 	}
 
-	// Prepare HTTP client
-	httpClient := &http.Client{Timeout: opts.Timeout}
-	if opts.InsecureTLS {
-		httpClient.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	if err := analysis.CheckClusterAccess(cfg.Provider); err != nil {
+		return err
 	}
-
-	// Prepare request
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, joinURL(baseURL, "/v1/query"), bytes.NewReader(bodyBytes))
+	llm, err := analysis.New(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
-
-	// Resolve token
-	token := resolveToken(opts.BearerToken, opts.TokenFile)
-	if token == "" {
-		// Try kubeconfig first
-		token = resolveTokenFromKubeconfig(opts.Kubeconfig, opts.KubeContext)
-		if token == "" {
-			// Try default in-cluster SA token
-			token = readFileIfExists(filepath.Join("/var/run/secrets/kubernetes.io/serviceaccount", "token"))
-		}
-	}
-	if token != "" {
-		req.Header.Set("Authorization", "Bearer "+token)
+		return err
 	}
 
-	// Execute request
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("request to Lightspeed failed: %w", err)
+	done = reporter.Start(progress.StageCallingLLM)
+	var result *analysis.Result
+	streamed := false
+	if opts.Stream && opts.Output == "text" {
+		result, streamed, err = analysis.Stream(ctx, llm, query, os.Stdout)
+	} else {
+		result, err = analysis.Run(ctx, llm, query)
 	}
-	defer safeClose(resp.Body)
-
-	respBody, err := io.ReadAll(resp.Body)
+	done(err)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return err
 	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("lightspeed returned %d: %s", resp.StatusCode, string(respBody))
+	if streamed {
+		fmt.Println()
+		return nil
 	}
+	result.Price(cfg.Pricing)
 
 	// Format and display the response based on output format
-	return formatOutput(string(respBody), opts.Output)
+	done = reporter.Start(progress.StageRendering)
+	err = formatOutput(result.JSON(), opts.Output, audience == analysis.AudienceExpert)
+	done(err)
+	return err
 }
 
 // formatOutput formats the API response according to the specified output format
-func formatOutput(response, format string) error {
+func formatOutput(response, format string, terse bool) error {
 	switch format {
 	case "json":
 		return formatJSON(response)
@@ -210,7 +204,7 @@ func formatOutput(response, format string) error {
 	case "text":
 		fallthrough
 	default:
-		return formatText(response)
+		return formatText(response, terse)
 	}
 }
 
@@ -249,19 +243,18 @@ func formatYAML(response string) error {
 }
 
 // formatText displays the response in a human-readable text format
-func formatText(response string) error {
+func formatText(response string, terse bool) error {
 	var jsonData interface{}
 	if err := json.Unmarshal([]byte(response), &jsonData); err != nil {
 		// If it's not valid JSON, print as-is with header
-		fmt.Println("API Response:")
-		fmt.Println("=============")
+		fmt.Println(i18n.Heading("API Response:"))
 		fmt.Println(response)
 		return nil
 	}
 
 	// Try to parse as structured data for better text formatting
 	if data, ok := jsonData.(map[string]interface{}); ok {
-		return displayStructuredText(data)
+		return displayStructuredText(data, terse)
 	}
 
 	// Fallback to pretty JSON if we can't structure it
@@ -271,18 +264,24 @@ func formatText(response string) error {
 		return nil
 	}
 
-	fmt.Println("API Response:")
-	fmt.Println("=============")
+	fmt.Println(i18n.Heading("API Response:"))
 	fmt.Println(string(prettyJSON))
 	return nil
 }
 
-// displayStructuredText formats structured JSON data as readable text for PipelineRun
-func displayStructuredText(data map[string]interface{}) error {
-	fmt.Println("PipelineRun Diagnosis Report")
-	fmt.Println("============================")
+// displayStructuredText formats structured JSON data as readable text for
+// PipelineRun. Terse output omits token usage and diagnosis metadata.
+func displayStructuredText(data map[string]interface{}, terse bool) error {
+	fmt.Println(i18n.Heading("PipelineRun Diagnosis Report"))
 	fmt.Println()
 
+	if c, ok := data[analysis.ConfidenceKey].(map[string]interface{}); ok {
+		level, _ := c["level"].(string)
+		score, _ := c["score"].(float64)
+		source, _ := c["source"].(string)
+		fmt.Printf("%s\n\n", i18n.Sprintf("Confidence: %s (%.2f, %s)", i18n.T(level), score, i18n.T(source)))
+	}
+
 	printed := false
 
 	// Prefer top-level LLM response if present. Handle embedded fenced JSON blocks.
@@ -290,7 +289,7 @@ func displayStructuredText(data map[string]interface{}) error {
 		if openIdx, contentStart, closeStart, okFence := findFence(resp); okFence {
 			preface := strings.TrimSpace(resp[:openIdx])
 			if preface != "" {
-				fmt.Printf("Summary:\n%s\n\n", preface)
+				fmt.Printf("%s\n%s\n\n", i18n.T("Summary:"), preface)
 				printed = true
 			}
 			inner := strings.TrimSpace(resp[contentStart:closeStart])
@@ -300,17 +299,16 @@ func displayStructuredText(data map[string]interface{}) error {
 			if len(inner) > 0 && (inner[0] == '{' || inner[0] == '[') && json.Unmarshal([]byte(inner), &embedded) == nil {
 				if obj, ok := embedded.(map[string]interface{}); ok {
 					if s, ok := obj["response"].(string); ok && s != "" && preface == "" {
-						fmt.Printf("Summary:\n%s\n\n", s)
+						fmt.Printf("%s\n%s\n\n", i18n.T("Summary:"), s)
 						printed = true
 					}
 					if a, ok := obj["analysis"].(string); ok && a != "" {
-						fmt.Printf("Analysis & Recommendations:\n")
-						fmt.Printf("===========================\n")
+						fmt.Println(i18n.Heading("Analysis & Recommendations:"))
 						fmt.Printf("%s\n\n", a)
 						printed = true
 					}
 					if sols, ok := obj["solutions"].([]interface{}); ok && len(sols) > 0 {
-						fmt.Println("Solutions:")
+						fmt.Println(i18n.T("Solutions:"))
 						for i, s := range sols {
 							if str, ok := s.(string); ok && str != "" {
 								fmt.Printf("  %d. %s\n", i+1, str)
@@ -326,15 +324,38 @@ func displayStructuredText(data map[string]interface{}) error {
 			clean := stripCodeFence(resp)
 			clean = truncateAtFence(clean)
 			if clean != "" {
-				fmt.Printf("Summary:\n%s\n\n", clean)
+				fmt.Printf("%s\n%s\n\n", i18n.T("Summary:"), clean)
 				printed = true
 			}
 		}
 	}
 
+	// Warn about YAML snippets that would not apply as written
+	if checks, ok := data[analysis.SnippetsKey].([]interface{}); ok {
+		warned := false
+		for _, c := range checks {
+			check, _ := c.(map[string]interface{})
+			if valid, _ := check["valid"].(bool); valid {
+				continue
+			}
+			index, _ := check["index"].(float64)
+			var problems []string
+			if ps, ok := check["problems"].([]interface{}); ok {
+				for _, p := range ps {
+					problems = append(problems, fmt.Sprint(p))
+				}
+			}
+			fmt.Printf("⚠️  %s\n", i18n.Sprintf("YAML snippet %d is not valid: %s", int(index), strings.Join(problems, "; ")))
+			warned = true
+		}
+		if warned {
+			fmt.Println()
+		}
+	}
+
 	// Print references if available
 	if refs, ok := data["referenced_documents"].([]interface{}); ok && len(refs) > 0 {
-		fmt.Println("References:")
+		fmt.Println(i18n.T("References:"))
 		count := 0
 		for _, r := range refs {
 			if rm, ok := r.(map[string]interface{}); ok {
@@ -357,23 +378,39 @@ func displayStructuredText(data map[string]interface{}) error {
 		fmt.Println()
 	}
 
-	if inTok, ok := data["input_tokens"].(float64); ok {
+	if inTok, ok := data["input_tokens"].(float64); ok && !terse {
 		if outTok, ok := data["output_tokens"].(float64); ok {
-			fmt.Printf("Token usage: input %.0f, output %.0f\n\n", inTok, outTok)
+			fmt.Printf("%s\n\n", i18n.Sprintf("Token usage: input %.0f, output %.0f", inTok, outTok))
 		}
 	}
 
+	if meta, ok := data[analysis.MetadataKey].(map[string]interface{}); ok && !terse {
+		provider, _ := meta["provider"].(string)
+		if model, ok := meta["model"].(string); ok && model != "" {
+			provider += " (" + model + ")"
+		}
+		version, _ := meta["prompt_version"].(string)
+		hash, _ := meta["prompt_hash"].(string)
+		duration, _ := meta["duration_ms"].(float64)
+		if usage, ok := meta["usage"].(map[string]interface{}); ok {
+			if cost, ok := usage["estimated_cost_usd"].(float64); ok {
+				fmt.Printf("%s\n\n", i18n.Sprintf("Estimated cost: $%.4f", cost))
+			}
+		}
+		fmt.Printf("%s\n\n", i18n.Sprintf("Analyzed by: %s, prompt %s/%s in %.0fms", provider, version, hash, duration))
+	}
+
 	// Display PipelineRun basic info
 	if pipelineRun, ok := data["pipelineRun"].(map[string]interface{}); ok {
 		if name, ok := pipelineRun["name"].(string); ok {
-			fmt.Printf("PipelineRun: %s\n", name)
+			fmt.Printf("%s: %s\n", i18n.T("PipelineRun"), name)
 			printed = true
 		}
 		if namespace, ok := pipelineRun["namespace"].(string); ok {
-			fmt.Printf("Namespace: %s\n", namespace)
+			fmt.Printf("%s: %s\n", i18n.T("Namespace"), namespace)
 		}
 		if uid, ok := pipelineRun["uid"].(string); ok {
-			fmt.Printf("UID: %s\n", uid)
+			fmt.Printf("%s: %s\n", i18n.T("UID"), uid)
 		}
 	}
 
@@ -383,29 +420,29 @@ func displayStructuredText(data map[string]interface{}) error {
 		if phase, ok := status["phase"].(string); ok {
 			switch phase {
 			case "Succeeded":
-				fmt.Printf("Status: ✅ %s\n", phase)
+				fmt.Printf("%s: ✅ %s\n", i18n.T("Status"), phase)
 			case "Failed":
-				fmt.Printf("Status: ❌ %s\n", phase)
+				fmt.Printf("%s: ❌ %s\n", i18n.T("Status"), phase)
 			case "Running":
-				fmt.Printf("Status: 🏃 %s\n", phase)
+				fmt.Printf("%s: 🏃 %s\n", i18n.T("Status"), phase)
 			default:
-				fmt.Printf("Status: %s\n", phase)
+				fmt.Printf("%s: %s\n", i18n.T("Status"), phase)
 			}
 		}
 
 		if startTime, ok := status["startTime"].(string); ok {
-			fmt.Printf("Start Time: %s\n", startTime)
+			fmt.Printf("%s: %s\n", i18n.T("Start Time"), startTime)
 		}
 		if completionTime, ok := status["completionTime"].(string); ok {
-			fmt.Printf("Completion Time: %s\n", completionTime)
+			fmt.Printf("%s: %s\n", i18n.T("Completion Time"), completionTime)
 		}
 		if duration, ok := status["durationSeconds"].(float64); ok {
-			fmt.Printf("Duration: %.0f seconds\n", duration)
+			fmt.Println(i18n.Sprintf("Duration: %.0f seconds", duration))
 		}
 
 		// Display conditions
 		if conditions, ok := status["conditions"].([]interface{}); ok && len(conditions) > 0 {
-			fmt.Println("\nConditions:")
+			fmt.Println("\n" + i18n.T("Conditions:"))
 			for _, condInterface := range conditions {
 				if cond, ok := condInterface.(map[string]interface{}); ok {
 					condType, _ := cond["type"].(string)
@@ -425,7 +462,7 @@ func displayStructuredText(data map[string]interface{}) error {
 
 					fmt.Printf("  %s %s: %s (%s)\n", statusIcon, condType, condStatus, reason)
 					if message != "" {
-						fmt.Printf("    Message: %s\n", message)
+						fmt.Printf("    %s: %s\n", i18n.T("Message"), message)
 					}
 				}
 			}
@@ -436,7 +473,7 @@ func displayStructuredText(data map[string]interface{}) error {
 	if failedTaskRuns, ok := data["failedTaskRuns"].([]interface{}); ok {
 		fmt.Println()
 		if len(failedTaskRuns) > 0 {
-			fmt.Printf("Failed TaskRuns (%d):\n", len(failedTaskRuns))
+			fmt.Println(i18n.Sprintf("Failed TaskRuns (%d):", len(failedTaskRuns)))
 			for i, taskRunInterface := range failedTaskRuns {
 				if taskRun, ok := taskRunInterface.(map[string]interface{}); ok {
 					name, _ := taskRun["name"].(string)
@@ -444,26 +481,25 @@ func displayStructuredText(data map[string]interface{}) error {
 					message, _ := taskRun["message"].(string)
 
 					fmt.Printf("  %d. ❌ %s\n", i+1, name)
-					fmt.Printf("     Reason: %s\n", reason)
+					fmt.Printf("     %s: %s\n", i18n.T("Reason"), reason)
 					if message != "" {
 						// Truncate long messages for better readability
 						if len(message) > 100 {
 							message = message[:97] + "..."
 						}
-						fmt.Printf("     Message: %s\n", message)
+						fmt.Printf("     %s: %s\n", i18n.T("Message"), message)
 					}
 					fmt.Println()
 				}
 			}
 		} else {
-			fmt.Println("Failed TaskRuns: None")
+			fmt.Println(i18n.T("Failed TaskRuns: None"))
 		}
 	}
 
 	// Display analysis
 	if analysis, ok := data["analysis"].(string); ok && analysis != "" {
-		fmt.Printf("Analysis & Recommendations:\n")
-		fmt.Printf("===========================\n")
+		fmt.Println(i18n.Heading("Analysis & Recommendations:"))
 		fmt.Printf("%s\n", analysis)
 		printed = true
 	}
@@ -471,7 +507,7 @@ func displayStructuredText(data map[string]interface{}) error {
 	// Display solutions if present
 	if sols, ok := data["solutions"].([]interface{}); ok {
 		if len(sols) > 0 {
-			fmt.Println("\nSolutions:")
+			fmt.Println("\n" + i18n.T("Solutions:"))
 			for i, s := range sols {
 				if str, ok := s.(string); ok && str != "" {
 					fmt.Printf("  %d. %s\n", i+1, str)
@@ -485,7 +521,7 @@ func displayStructuredText(data map[string]interface{}) error {
 	if !printed {
 		for _, key := range []string{"answer", "response", "result", "message", "content", "text", "output"} {
 			if v, ok := data[key].(string); ok && v != "" {
-				fmt.Printf("\nResponse:\n%s\n", v)
+				fmt.Printf("\n%s\n%s\n", i18n.T("Response:"), v)
 				printed = true
 				break
 			}
@@ -509,7 +545,7 @@ func displayStructuredText(data map[string]interface{}) error {
 				}
 			}
 			if combined != "" {
-				fmt.Printf("\nResponse:\n%s", combined)
+				fmt.Printf("\n%s\n%s", i18n.T("Response:"), combined)
 				printed = true
 			}
 		}
@@ -518,8 +554,7 @@ func displayStructuredText(data map[string]interface{}) error {
 	if !printed {
 		b, err := json.MarshalIndent(data, "", "  ")
 		if err == nil {
-			fmt.Println("API Response:")
-			fmt.Println("=============")
+			fmt.Println(i18n.Heading("API Response:"))
 			fmt.Println(string(b))
 		}
 	}
@@ -530,45 +565,6 @@ func displayStructuredText(data map[string]interface{}) error {
 
 // --- helpers ---
 
-func joinURL(base, path string) string {
-	if base == "" {
-		return path
-	}
-	if len(base) > 0 && base[len(base)-1] == '/' {
-		base = base[:len(base)-1]
-	}
-	if len(path) > 0 && path[0] == '/' {
-		return base + path
-	}
-	return base + "/" + path
-}
-
-func resolveToken(tokenFlag, tokenFile string) string {
-	if tokenFlag != "" {
-		return tokenFlag
-	}
-	if tokenFile != "" {
-		if b, err := os.ReadFile(tokenFile); err == nil {
-			return string(bytes.TrimSpace(b))
-		}
-	}
-	if env := os.Getenv("LIGHTSPEED_TOKEN"); env != "" {
-		return env
-	}
-	return ""
-}
-
-func readFileIfExists(path string) string {
-	if b, err := os.ReadFile(path); err == nil {
-		return string(bytes.TrimSpace(b))
-	}
-	return ""
-}
-
-func safeClose(c io.Closer) {
-	_ = c.Close()
-}
-
 // findFence locates the first ``` fenced code block and returns indexes to its contents
 func findFence(s string) (openIdx, contentStart, closeStart int, ok bool) {
 	openIdx = strings.Index(s, "```")
@@ -623,86 +619,3 @@ func stripCodeFence(s string) string {
 	}
 	return s
 }
-
-// resolveTokenFromKubeconfig tries to extract a bearer token from kubeconfig via YAML parsing
-func resolveTokenFromKubeconfig(kubeconfigPath, contextName string) string {
-	if kubeconfigPath == "" {
-		if env := os.Getenv("KUBECONFIG"); env != "" {
-			parts := strings.Split(env, string(os.PathListSeparator))
-			if len(parts) > 0 {
-				kubeconfigPath = parts[0]
-			}
-		} else {
-			if home, err := os.UserHomeDir(); err == nil {
-				kubeconfigPath = filepath.Join(home, ".kube", "config")
-			}
-		}
-	}
-	if kubeconfigPath == "" {
-		return ""
-	}
-
-	data, err := os.ReadFile(kubeconfigPath)
-	if err != nil {
-		return ""
-	}
-
-	type kcUser struct {
-		Token     string `yaml:"token"`
-		TokenFile string `yaml:"token-file"`
-	}
-	type kcUserEntry struct {
-		Name string `yaml:"name"`
-		User kcUser `yaml:"user"`
-	}
-	type kcContext struct {
-		User string `yaml:"user"`
-	}
-	type kcContextEntry struct {
-		Name    string    `yaml:"name"`
-		Context kcContext `yaml:"context"`
-	}
-	type kubeconfig struct {
-		CurrentContext string           `yaml:"current-context"`
-		Contexts       []kcContextEntry `yaml:"contexts"`
-		Users          []kcUserEntry    `yaml:"users"`
-	}
-
-	var cfg kubeconfig
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return ""
-	}
-
-	current := contextName
-	if current == "" {
-		current = cfg.CurrentContext
-	}
-	if current == "" {
-		return ""
-	}
-	var userName string
-	for _, c := range cfg.Contexts {
-		if c.Name == current {
-			userName = c.Context.User
-			break
-		}
-	}
-	if userName == "" {
-		return ""
-	}
-	for _, u := range cfg.Users {
-		if u.Name == userName {
-			if u.User.Token != "" {
-				return u.User.Token
-			}
-			if u.User.TokenFile != "" {
-				if b, err := os.ReadFile(u.User.TokenFile); err == nil {
-					return string(bytes.TrimSpace(b))
-				}
-			}
-		}
-	}
-	return ""
-}
-
-// removed duplicate kubeconfig resolver
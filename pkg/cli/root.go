@@ -15,14 +15,20 @@
 package cli
 
 import (
+	chatcmd "github.com/openshift-pipelines/tekton-assist/pkg/cli/chat"
+	diagnosecmd "github.com/openshift-pipelines/tekton-assist/pkg/cli/diagnose"
+	doctorcmd "github.com/openshift-pipelines/tekton-assist/pkg/cli/doctor"
+	explaincmd "github.com/openshift-pipelines/tekton-assist/pkg/cli/explain"
 	prcmd "github.com/openshift-pipelines/tekton-assist/pkg/cli/pipelinerun"
 	trcmd "github.com/openshift-pipelines/tekton-assist/pkg/cli/taskrun"
+	"github.com/openshift-pipelines/tekton-assist/pkg/i18n"
 	"github.com/spf13/cobra"
 )
 
 // RootCommand returns the root command for the assist CLI. Consumers (like OPC)
 // can import this package and mount the returned command under their own root.
 func RootCommand() *cobra.Command {
+	var language string
 	root := &cobra.Command{
 		Use:   "tkn-assist",
 		Short: "AI-assisted diagnosis for Tekton",
@@ -30,11 +36,21 @@ func RootCommand() *cobra.Command {
 		Annotations: map[string]string{
 			"commandType": "main",
 		},
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			if cmd.Flags().Changed("language") {
+				i18n.SetLanguage(language)
+			}
+		},
 	}
+	root.PersistentFlags().StringVar(&language, "language", "", "Language of the analysis and report labels, as a BCP 47 tag such as ja or pt-BR (default: "+i18n.LanguageEnv+" or the locale)")
 
 	// Add top-level groups
 	root.AddCommand(trcmd.TaskRunCommand())
 	root.AddCommand(prcmd.PipelineRunCommand())
+	root.AddCommand(diagnosecmd.DiagnoseCommand())
+	root.AddCommand(explaincmd.ExplainCommand())
+	root.AddCommand(chatcmd.ChatCommand())
+	root.AddCommand(doctorcmd.DoctorCommand())
 
 	return root
 }
@@ -15,42 +15,40 @@
 package taskrun
 
 import (
-	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/openshift-pipelines/tekton-assist/pkg/analysis"
+	"github.com/openshift-pipelines/tekton-assist/pkg/cli/options"
+	"github.com/openshift-pipelines/tekton-assist/pkg/i18n"
+	"github.com/openshift-pipelines/tekton-assist/pkg/progress"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 )
 
 // DiagnoseOptions holds options specific to the diagnose command
 type DiagnoseOptions struct {
-	TaskRunName   string
-	Output        string
-	Namespace     string
-	Verbose       bool
-	Kubeconfig    string
-	KubeContext   string
-	LightspeedURL string
-	BearerToken   string
-	TokenFile     string
-	InsecureTLS   bool
-	Timeout       time.Duration
+	TaskRunName string
+	Output      string
+	Namespace   string
+	Verbose     bool
+	Progress    string
+	Stream      bool
+	Hint        string
+	Audience    string
+
+	options.ProviderOptions
 }
 
 // DiagnoseCommand creates the diagnose command for TaskRuns
 func DiagnoseCommand() *cobra.Command {
 	opts := &DiagnoseOptions{
-		Output:  "text",
-		Timeout: 30 * time.Second,
+		Output:          "text",
+		ProviderOptions: options.NewProviderOptions(30 * time.Second),
 	}
 
 	diagnoseCmd := &cobra.Command{
@@ -86,23 +84,30 @@ The command will:
 	// Command-specific flags
 	diagnoseCmd.Flags().StringVarP(&opts.Output, "output", "o", "text", "Output format (text, json, yaml)")
 	diagnoseCmd.Flags().StringVarP(&opts.Namespace, "namespace", "n", "", "Kubernetes namespace")
-	diagnoseCmd.Flags().StringVar(&opts.Kubeconfig, "kubeconfig", "", "Path to kubeconfig file")
-	diagnoseCmd.Flags().StringVar(&opts.KubeContext, "context", "", "Kubernetes context to use")
-	diagnoseCmd.Flags().StringVar(&opts.LightspeedURL, "lightspeed-url", "", "Lightspeed service base URL (default: https://localhost:8443)")
-	diagnoseCmd.Flags().StringVar(&opts.BearerToken, "token", "", "Bearer token for Lightspeed service (or set LIGHTSPEED_TOKEN)")
-	diagnoseCmd.Flags().StringVar(&opts.TokenFile, "token-file", "", "Path to a file containing the bearer token")
-	diagnoseCmd.Flags().BoolVarP(&opts.InsecureTLS, "insecure-skip-tls-verify", "k", false, "Skip TLS certificate verification (insecure)")
-	diagnoseCmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Second, "Timeout for API requests")
+	opts.AddFlags(diagnoseCmd)
+	diagnoseCmd.Flags().StringVar(&opts.Hint, "hint", "", "Extra context for the analysis (e.g. \"we upgraded the base image yesterday\")")
+	diagnoseCmd.Flags().StringVar(&opts.Audience, "audience", string(analysis.AudienceStandard), "Tailor the explanation to the reader. One of: beginner|standard|expert")
+	diagnoseCmd.Flags().StringVar(&opts.Progress, "progress", "none", "Emit progress events to stderr. One of: none|ndjson")
+	diagnoseCmd.Flags().BoolVar(&opts.Stream, "stream", false, "Print the analysis as it is generated (text output only)")
 
 	return diagnoseCmd
 }
 
 // runDiagnose executes the diagnosis workflow
 func runDiagnose(ctx context.Context, opts *DiagnoseOptions) error {
+	reporter, err := progress.New(opts.Progress, os.Stderr)
+	if err != nil {
+		return err
+	}
+	audience, err := analysis.ParseAudience(opts.Audience)
+	if err != nil {
+		return err
+	}
+
 	if opts.Verbose {
 		fmt.Printf("Diagnosing TaskRun: %s\n", opts.TaskRunName)
 		if opts.Namespace != "" {
-			fmt.Printf("Namespace: %s\n", opts.Namespace)
+			fmt.Printf("%s: %s\n", i18n.T("Namespace"), opts.Namespace)
 		}
 		fmt.Printf("Output format: %s\n", opts.Output)
 		if opts.LightspeedURL != "" {
@@ -113,10 +118,10 @@ func runDiagnose(ctx context.Context, opts *DiagnoseOptions) error {
 	// Determine the Lightspeed base URL
 	baseURL := opts.LightspeedURL
 	if baseURL == "" {
-		baseURL = "https://localhost:8443"
+		baseURL = analysis.DefaultLightspeedURL
 	}
 
-	if opts.Verbose {
+	if opts.Verbose && opts.Provider == analysis.ProviderLightspeed {
 		fmt.Printf("Connecting to Lightspeed at: %s\n", baseURL)
 	}
 
@@ -129,71 +134,57 @@ func runDiagnose(ctx context.Context, opts *DiagnoseOptions) error {
 		}
 	}
 
-	// Build query payload (chat-style phrasing + ask for solutions + JSON shape)
-	query := fmt.Sprintf(
-		"Why is my Tekton TaskRun '%s' failing in namespace '%s'? "+
-			"Provide a brief summary, a clear root-cause analysis, and 3-5 actionable solutions. "+
-			"If possible, respond as a JSON object with fields: response (string), analysis (string), solutions (array of strings).",
-		opts.TaskRunName, namespace,
-	)
+	query := analysis.TaskRunQuery(opts.TaskRunName, namespace)
+	query = analysis.WithAudience(query, audience)
+	query = analysis.WithLanguage(query, i18n.Name(i18n.Requested()))
+	query = analysis.WithHint(query, opts.Hint)
 	if opts.Verbose {
 		fmt.Printf("Query: %s\n", query)
 	}
 
-	payload := map[string]interface{}{
-		"query": query,
-	}
-	bodyBytes, err := json.Marshal(payload)
+	// Resolve token
+	done := reporter.Start(progress.StageResolvingCredential)
+	cfg, err := opts.Config()
+	done(err)
 	if err != nil {
-		return fmt.Errorf("failed to marshal request body: %w", err)
+		return err
 	}
 
-	// Prepare HTTP client
-	httpClient := &http.Client{Timeout: opts.Timeout}
-	if opts.InsecureTLS {
-		transport := &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
-		httpClient.Transport = transport
+	if err := analysis.CheckClusterAccess(cfg.Provider); err != nil {
+		return err
 	}
-
-	// Prepare request
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, joinURL(baseURL, "/v1/query"), bytes.NewReader(bodyBytes))
+	llm, err := analysis.New(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
-
-	// Resolve token
-	token := resolveToken(opts.BearerToken, opts.TokenFile)
-	if token == "" {
-		token = resolveTokenFromKubeconfig(opts.Kubeconfig, opts.KubeContext)
-	}
-	if token != "" {
-		req.Header.Set("Authorization", "Bearer "+token)
+		return err
 	}
 
-	// Execute request
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("request to Lightspeed failed: %w", err)
+	done = reporter.Start(progress.StageCallingLLM)
+	var result *analysis.Result
+	streamed := false
+	if opts.Stream && opts.Output == "text" {
+		result, streamed, err = analysis.Stream(ctx, llm, query, os.Stdout)
+	} else {
+		result, err = analysis.Run(ctx, llm, query)
 	}
-	defer safeClose(resp.Body)
-
-	respBody, err := io.ReadAll(resp.Body)
+	done(err)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return err
 	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("lightspeed returned %d: %s", resp.StatusCode, string(respBody))
+	if streamed {
+		fmt.Println()
+		return nil
 	}
+	result.Price(cfg.Pricing)
 
 	// Format and display the response based on output format
-	return formatOutput(string(respBody), opts.Output)
+	done = reporter.Start(progress.StageRendering)
+	err = formatOutput(result.JSON(), opts.Output, audience == analysis.AudienceExpert)
+	done(err)
+	return err
 }
 
 // formatOutput formats the API response according to the specified output format
-func formatOutput(response, format string) error {
+func formatOutput(response, format string, terse bool) error {
 	switch format {
 	case "json":
 		return formatJSON(response)
@@ -202,7 +193,7 @@ func formatOutput(response, format string) error {
 	case "text":
 		fallthrough
 	default:
-		return formatText(response)
+		return formatText(response, terse)
 	}
 }
 
@@ -245,19 +236,18 @@ func formatYAML(response string) error {
 }
 
 // formatText displays the response in a human-readable text format
-func formatText(response string) error {
+func formatText(response string, terse bool) error {
 	var jsonData interface{}
 	if err := json.Unmarshal([]byte(response), &jsonData); err != nil {
 		// If it's not valid JSON, print as-is with header
-		fmt.Println("API Response:")
-		fmt.Println("=============")
+		fmt.Println(i18n.Heading("API Response:"))
 		fmt.Println(response)
 		return nil
 	}
 
 	// Try to parse as structured data for better text formatting
 	if data, ok := jsonData.(map[string]interface{}); ok {
-		return displayStructuredText(data)
+		return displayStructuredText(data, terse)
 	}
 
 	// Fallback to pretty JSON if we can't structure it
@@ -267,18 +257,24 @@ func formatText(response string) error {
 		return nil
 	}
 
-	fmt.Println("API Response:")
-	fmt.Println("=============")
+	fmt.Println(i18n.Heading("API Response:"))
 	fmt.Println(string(prettyJSON))
 	return nil
 }
 
-// displayStructuredText formats structured JSON data as readable text
-func displayStructuredText(data map[string]interface{}) error {
-	fmt.Println("TaskRun Diagnosis Report")
-	fmt.Println("========================")
+// displayStructuredText formats structured JSON data as readable text. Terse
+// output omits token usage and diagnosis metadata.
+func displayStructuredText(data map[string]interface{}, terse bool) error {
+	fmt.Println(i18n.Heading("TaskRun Diagnosis Report"))
 	fmt.Println()
 
+	if c, ok := data[analysis.ConfidenceKey].(map[string]interface{}); ok {
+		level, _ := c["level"].(string)
+		score, _ := c["score"].(float64)
+		source, _ := c["source"].(string)
+		fmt.Printf("%s\n\n", i18n.Sprintf("Confidence: %s (%.2f, %s)", i18n.T(level), score, i18n.T(source)))
+	}
+
 	printed := false
 
 	// Prefer top-level LLM response if present. Handle embedded fenced JSON blocks.
@@ -286,7 +282,7 @@ func displayStructuredText(data map[string]interface{}) error {
 		if openIdx, contentStart, closeStart, okFence := findFence(resp); okFence {
 			preface := strings.TrimSpace(resp[:openIdx])
 			if preface != "" {
-				fmt.Printf("Summary:\n%s\n\n", preface)
+				fmt.Printf("%s\n%s\n\n", i18n.T("Summary:"), preface)
 				printed = true
 			}
 			inner := strings.TrimSpace(resp[contentStart:closeStart])
@@ -296,15 +292,15 @@ func displayStructuredText(data map[string]interface{}) error {
 			if len(inner) > 0 && (inner[0] == '{' || inner[0] == '[') && json.Unmarshal([]byte(inner), &embedded) == nil {
 				if obj, ok := embedded.(map[string]interface{}); ok {
 					if s, ok := obj["response"].(string); ok && s != "" && preface == "" {
-						fmt.Printf("Summary:\n%s\n\n", s)
+						fmt.Printf("%s\n%s\n\n", i18n.T("Summary:"), s)
 						printed = true
 					}
 					if a, ok := obj["analysis"].(string); ok && a != "" {
-						fmt.Printf("Analysis & Suggested Remediation:\n%s\n\n", a)
+						fmt.Printf("%s\n%s\n\n", i18n.T("Analysis & Suggested Remediation:"), a)
 						printed = true
 					}
 					if sols, ok := obj["solutions"].([]interface{}); ok && len(sols) > 0 {
-						fmt.Println("Solutions:")
+						fmt.Println(i18n.T("Solutions:"))
 						for i, s := range sols {
 							if str, ok := s.(string); ok && str != "" {
 								fmt.Printf("  %d. %s\n", i+1, str)
@@ -320,15 +316,38 @@ func displayStructuredText(data map[string]interface{}) error {
 			clean := stripCodeFence(resp)
 			clean = truncateAtFence(clean)
 			if clean != "" {
-				fmt.Printf("Summary:\n%s\n\n", clean)
+				fmt.Printf("%s\n%s\n\n", i18n.T("Summary:"), clean)
 				printed = true
 			}
 		}
 	}
 
+	// Warn about YAML snippets that would not apply as written
+	if checks, ok := data[analysis.SnippetsKey].([]interface{}); ok {
+		warned := false
+		for _, c := range checks {
+			check, _ := c.(map[string]interface{})
+			if valid, _ := check["valid"].(bool); valid {
+				continue
+			}
+			index, _ := check["index"].(float64)
+			var problems []string
+			if ps, ok := check["problems"].([]interface{}); ok {
+				for _, p := range ps {
+					problems = append(problems, fmt.Sprint(p))
+				}
+			}
+			fmt.Printf("⚠️  %s\n", i18n.Sprintf("YAML snippet %d is not valid: %s", int(index), strings.Join(problems, "; ")))
+			warned = true
+		}
+		if warned {
+			fmt.Println()
+		}
+	}
+
 	// Print references if available
 	if refs, ok := data["referenced_documents"].([]interface{}); ok && len(refs) > 0 {
-		fmt.Println("References:")
+		fmt.Println(i18n.T("References:"))
 		count := 0
 		for _, r := range refs {
 			if rm, ok := r.(map[string]interface{}); ok {
@@ -352,57 +371,73 @@ func displayStructuredText(data map[string]interface{}) error {
 	}
 
 	// Token usage (optional diagnostics)
-	if inTok, ok := data["input_tokens"].(float64); ok {
+	if inTok, ok := data["input_tokens"].(float64); ok && !terse {
 		if outTok, ok := data["output_tokens"].(float64); ok {
-			fmt.Printf("Token usage: input %.0f, output %.0f\n\n", inTok, outTok)
+			fmt.Printf("%s\n\n", i18n.Sprintf("Token usage: input %.0f, output %.0f", inTok, outTok))
 		}
 	}
 
+	if meta, ok := data[analysis.MetadataKey].(map[string]interface{}); ok && !terse {
+		provider, _ := meta["provider"].(string)
+		if model, ok := meta["model"].(string); ok && model != "" {
+			provider += " (" + model + ")"
+		}
+		version, _ := meta["prompt_version"].(string)
+		hash, _ := meta["prompt_hash"].(string)
+		duration, _ := meta["duration_ms"].(float64)
+		if usage, ok := meta["usage"].(map[string]interface{}); ok {
+			if cost, ok := usage["estimated_cost_usd"].(float64); ok {
+				fmt.Printf("%s\n\n", i18n.Sprintf("Estimated cost: $%.4f", cost))
+			}
+		}
+		fmt.Printf("%s\n\n", i18n.Sprintf("Analyzed by: %s, prompt %s/%s in %.0fms", provider, version, hash, duration))
+	}
+
 	// Handle the actual JSON structure from the server
 	if debug, ok := data["debug"].(map[string]interface{}); ok {
 		// Display basic info
 		if taskrun, ok := debug["taskrun"].(string); ok {
-			fmt.Printf("TaskRun: %s\n", taskrun)
+			fmt.Printf("%s: %s\n", i18n.T("TaskRun"), taskrun)
 		}
 		if namespace, ok := debug["namespace"].(string); ok {
-			fmt.Printf("Namespace: %s\n", namespace)
+			fmt.Printf("%s: %s\n", i18n.T("Namespace"), namespace)
 		}
 		if succeeded, ok := debug["succeeded"].(bool); ok {
 			if succeeded {
-				fmt.Printf("Succeeded: ✅ Yes\n")
+				fmt.Printf("%s: ✅ %s\n", i18n.T("Succeeded"), i18n.T("Yes"))
 			} else {
-				fmt.Printf("Succeeded: ❌ No\n")
+				fmt.Printf("%s: ❌ %s\n", i18n.T("Succeeded"), i18n.T("No"))
 			}
 		}
 
 		// Display failed step info
 		if failedStep, ok := debug["failed_step"].(map[string]interface{}); ok {
 			if name, ok := failedStep["name"].(string); ok {
-				fmt.Printf("Failed Step: %s\n", name)
+				fmt.Printf("%s: %s\n", i18n.T("Failed Step"), name)
 			}
 			if exitCode, ok := failedStep["exit_code"].(float64); ok {
-				fmt.Printf("Exit Code: %.0f\n", exitCode)
+				fmt.Printf("%s: %.0f\n", i18n.T("Exit Code"), exitCode)
 			}
 		}
 
 		// Display error details
 		if errorInfo, ok := debug["error"].(map[string]interface{}); ok {
-			fmt.Println("\nError Details:")
+			fmt.Println("\n" + i18n.T("Error Details:"))
 			if errorType, ok := errorInfo["type"].(string); ok {
-				fmt.Printf("Type: %s\n", errorType)
+				fmt.Printf("%s: %s\n", i18n.T("Type"), errorType)
 			}
 			if status, ok := errorInfo["status"].(string); ok {
-				fmt.Printf("Status: %s\n", status)
+				fmt.Printf("%s: %s\n", i18n.T("Status"), status)
 			}
 			if reason, ok := errorInfo["reason"].(string); ok {
-				fmt.Printf("Reason: %s\n", reason)
+				fmt.Printf("%s: %s\n", i18n.T("Reason"), reason)
 			}
 			if message, ok := errorInfo["message"].(string); ok {
-				fmt.Printf("Message: %s\n", message)
+				fmt.Printf("%s: %s\n", i18n.T("Message"), message)
 			}
 			if logSnippet, ok := errorInfo["log_snippet"].(string); ok {
 				if logSnippet != "" && logSnippet != errorInfo["message"] {
-					fmt.Printf("\nLog Snippet:\n%s\n", logSnippet)
+					fmt.Printf("\n%s\n%s\n", i18n.T("Log Snippet:"), logSnippet)
 				}
 			}
 		}
@@ -411,14 +446,14 @@ func displayStructuredText(data map[string]interface{}) error {
 
 	// Display analysis if present
 	if analysis, ok := data["analysis"].(string); ok && analysis != "" {
-		fmt.Printf("\nAnalysis & Suggested Remediation:\n%s\n", analysis)
+		fmt.Printf("\n%s\n%s\n", i18n.T("Analysis & Suggested Remediation:"), analysis)
 		printed = true
 	}
 
 	// Display solutions if present
 	if sols, ok := data["solutions"].([]interface{}); ok {
 		if len(sols) > 0 {
-			fmt.Println("\nSolutions:")
+			fmt.Println("\n" + i18n.T("Solutions:"))
 			for i, s := range sols {
 				if str, ok := s.(string); ok && str != "" {
 					fmt.Printf("  %d. %s\n", i+1, str)
@@ -432,7 +467,7 @@ func displayStructuredText(data map[string]interface{}) error {
 	if !printed {
 		for _, key := range []string{"answer", "response", "result", "message", "content", "text", "output"} {
 			if v, ok := data[key].(string); ok && v != "" {
-				fmt.Printf("\nResponse:\n%s\n", v)
+				fmt.Printf("\n%s\n%s\n", i18n.T("Response:"), v)
 				printed = true
 				break
 			}
@@ -458,7 +493,7 @@ func displayStructuredText(data map[string]interface{}) error {
 				}
 			}
 			if combined != "" {
-				fmt.Printf("\nResponse:\n%s", combined)
+				fmt.Printf("\n%s\n%s", i18n.T("Response:"), combined)
 				printed = true
 			}
 		}
@@ -468,8 +503,7 @@ func displayStructuredText(data map[string]interface{}) error {
 	if !printed {
 		b, err := json.MarshalIndent(data, "", "  ")
 		if err == nil {
-			fmt.Println("API Response:")
-			fmt.Println("=============")
+			fmt.Println(i18n.Heading("API Response:"))
 			fmt.Println(string(b))
 		}
 	}
@@ -506,15 +540,15 @@ func (r *DiagnoseResult) Display(format string) error {
 }
 
 func (r *DiagnoseResult) displayText() error {
-	fmt.Printf("TaskRun Diagnosis Report\n")
-	fmt.Printf("========================\n\n")
-	fmt.Printf("TaskRun: %s\n", r.TaskRunName)
-	fmt.Printf("Namespace: %s\n", r.Namespace)
-	fmt.Printf("Status: %s\n", r.Status)
-	fmt.Printf("Analyzed at: %s\n\n", r.Timestamp.Format(time.RFC3339))
+	fmt.Println(i18n.Heading("TaskRun Diagnosis Report"))
+	fmt.Println()
+	fmt.Printf("%s: %s\n", i18n.T("TaskRun"), r.TaskRunName)
+	fmt.Printf("%s: %s\n", i18n.T("Namespace"), r.Namespace)
+	fmt.Printf("%s: %s\n", i18n.T("Status"), r.Status)
+	fmt.Printf("%s: %s\n\n", i18n.T("Analyzed at"), r.Timestamp.Format(time.RFC3339))
 
 	if len(r.FailedSteps) > 0 {
-		fmt.Printf("Failed Steps:\n")
+		fmt.Println(i18n.T("Failed Steps:"))
 		for _, step := range r.FailedSteps {
 			fmt.Printf("  - %s\n", step)
 		}
@@ -522,17 +556,17 @@ func (r *DiagnoseResult) displayText() error {
 	}
 
 	if len(r.ErrorMessages) > 0 {
-		fmt.Printf("Error Messages:\n")
+		fmt.Println(i18n.T("Error Messages:"))
 		for _, msg := range r.ErrorMessages {
 			fmt.Printf("  - %s\n", msg)
 		}
 		fmt.Printf("\n")
 	}
 
-	fmt.Printf("Analysis:\n%s\n\n", r.Analysis)
+	fmt.Printf("%s\n%s\n\n", i18n.T("Analysis:"), r.Analysis)
 
 	if len(r.Suggestions) > 0 {
-		fmt.Printf("Recommendations:\n")
+		fmt.Println(i18n.T("Recommendations:"))
 		for i, suggestion := range r.Suggestions {
 			fmt.Printf("  %d. %s\n", i+1, suggestion)
 		}
@@ -553,38 +587,6 @@ func (r *DiagnoseResult) displayYAML() error {
 
 // --- helpers ---
 
-func joinURL(base, path string) string {
-	if base == "" {
-		return path
-	}
-	if len(base) > 0 && base[len(base)-1] == '/' {
-		base = base[:len(base)-1]
-	}
-	if len(path) > 0 && path[0] == '/' {
-		return base + path
-	}
-	return base + "/" + path
-}
-
-func resolveToken(tokenFlag, tokenFile string) string {
-	if tokenFlag != "" {
-		return tokenFlag
-	}
-	if tokenFile != "" {
-		if b, err := os.ReadFile(tokenFile); err == nil {
-			return string(bytes.TrimSpace(b))
-		}
-	}
-	if env := os.Getenv("LIGHTSPEED_TOKEN"); env != "" {
-		return env
-	}
-	return ""
-}
-
-func safeClose(c io.Closer) {
-	_ = c.Close()
-}
-
 // stripCodeFence removes leading/trailing markdown code fences if present
 func stripCodeFence(s string) string {
 	s = strings.TrimSpace(s)
@@ -641,89 +643,3 @@ func stripFenceLanguage(s string) string {
 	}
 	return s
 }
-
-// resolveTokenFromKubeconfig tries to extract a bearer token from kubeconfig via YAML parsing
-func resolveTokenFromKubeconfig(kubeconfigPath, contextName string) string {
-	// Determine kubeconfig path
-	if kubeconfigPath == "" {
-		if env := os.Getenv("KUBECONFIG"); env != "" {
-			// If multiple paths, take the first
-			parts := strings.Split(env, string(os.PathListSeparator))
-			if len(parts) > 0 {
-				kubeconfigPath = parts[0]
-			}
-		} else {
-			if home, err := os.UserHomeDir(); err == nil {
-				kubeconfigPath = filepath.Join(home, ".kube", "config")
-			}
-		}
-	}
-	if kubeconfigPath == "" {
-		return ""
-	}
-
-	data, err := os.ReadFile(kubeconfigPath)
-	if err != nil {
-		return ""
-	}
-
-	// Minimal kubeconfig model
-	type kcUser struct {
-		Token     string `yaml:"token"`
-		TokenFile string `yaml:"token-file"`
-	}
-	type kcUserEntry struct {
-		Name string `yaml:"name"`
-		User kcUser `yaml:"user"`
-	}
-	type kcContext struct {
-		User string `yaml:"user"`
-	}
-	type kcContextEntry struct {
-		Name    string    `yaml:"name"`
-		Context kcContext `yaml:"context"`
-	}
-	type kubeconfig struct {
-		CurrentContext string           `yaml:"current-context"`
-		Contexts       []kcContextEntry `yaml:"contexts"`
-		Users          []kcUserEntry    `yaml:"users"`
-	}
-
-	var cfg kubeconfig
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return ""
-	}
-
-	current := contextName
-	if current == "" {
-		current = cfg.CurrentContext
-	}
-	if current == "" {
-		return ""
-	}
-
-	var userName string
-	for _, c := range cfg.Contexts {
-		if c.Name == current {
-			userName = c.Context.User
-			break
-		}
-	}
-	if userName == "" {
-		return ""
-	}
-
-	for _, u := range cfg.Users {
-		if u.Name == userName {
-			if u.User.Token != "" {
-				return u.User.Token
-			}
-			if u.User.TokenFile != "" {
-				if b, err := os.ReadFile(u.User.TokenFile); err == nil {
-					return string(bytes.TrimSpace(b))
-				}
-			}
-		}
-	}
-	return ""
-}
@@ -0,0 +1,137 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doctor
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/auth"
+	"github.com/openshift-pipelines/tekton-assist/pkg/kube"
+)
+
+// clusterClient adds the calls the checks need to kube.Client
+type clusterClient struct {
+	*kube.Client
+}
+
+func newClusterClient(c *auth.Cluster, timeout time.Duration) (*clusterClient, error) {
+	client, err := kube.NewClient(c, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &clusterClient{Client: client}, nil
+}
+
+func (c *clusterClient) serverVersion(ctx context.Context) (string, error) {
+	var v struct {
+		GitVersion string `json:"gitVersion"`
+	}
+	if err := c.Do(ctx, http.MethodGet, "/version", nil, &v); err != nil {
+		return "", err
+	}
+	return v.GitVersion, nil
+}
+
+type groupVersion struct {
+	GroupVersion string `json:"groupVersion"`
+	Version      string `json:"version"`
+}
+
+type apiGroup struct {
+	Versions         []groupVersion `json:"versions"`
+	PreferredVersion groupVersion   `json:"preferredVersion"`
+}
+
+func (g *apiGroup) versions() []string {
+	var vs []string
+	for _, v := range g.Versions {
+		vs = append(vs, v.Version)
+	}
+	return vs
+}
+
+func (g *apiGroup) serves(version string) bool {
+	for _, v := range g.Versions {
+		if v.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *clusterClient) tektonGroup(ctx context.Context) (*apiGroup, error) {
+	var g apiGroup
+	if err := c.Do(ctx, http.MethodGet, "/apis/tekton.dev", nil, &g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// resourceAccess is a verb on a namespaced resource
+type resourceAccess struct {
+	Group       string `json:"group"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+	Verb        string `json:"verb"`
+	Namespace   string `json:"namespace,omitempty"`
+}
+
+func (a resourceAccess) String() string {
+	r := a.Resource
+	if a.Subresource != "" {
+		r += "/" + a.Subresource
+	}
+	return a.Verb + " " + r
+}
+
+// canI asks the API server whether the current user may perform a
+func (c *clusterClient) canI(ctx context.Context, namespace string, a resourceAccess) (bool, error) {
+	a.Namespace = namespace
+	review := map[string]interface{}{
+		"apiVersion": "authorization.k8s.io/v1",
+		"kind":       "SelfSubjectAccessReview",
+		"spec":       map[string]interface{}{"resourceAttributes": a},
+	}
+	var resp struct {
+		Status struct {
+			Allowed bool `json:"allowed"`
+		} `json:"status"`
+	}
+	if err := c.Do(ctx, http.MethodPost, "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews", review, &resp); err != nil {
+		return false, err
+	}
+	return resp.Status.Allowed, nil
+}
+
+// clusterFixes suggests remediations for a failed API server call
+func clusterFixes(err error) []string {
+	var apiErr *kube.StatusError
+	var unknownAuthority x509.UnknownAuthorityError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.Status == http.StatusUnauthorized:
+		return []string{"your cluster credentials are missing or expired; log in again with `oc login`"}
+	case errors.As(err, &apiErr) && apiErr.Status == http.StatusForbidden:
+		return []string{"your user may not perform this call; ask a cluster admin for access"}
+	case errors.As(err, &unknownAuthority):
+		return []string{"the API server certificate is not trusted; set certificate-authority-data in the kubeconfig"}
+	case errors.Is(err, context.DeadlineExceeded):
+		return []string{"the API server did not answer in time; check VPN/proxy settings or retry with a larger --timeout"}
+	}
+	return []string{"check that the API server in the kubeconfig is reachable from this machine"}
+}
@@ -0,0 +1,234 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package doctor checks that tekton-assist can reach the cluster and the
+// analysis provider and reports what to fix when it cannot
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/analysis"
+	"github.com/openshift-pipelines/tekton-assist/pkg/auth"
+	"github.com/openshift-pipelines/tekton-assist/pkg/kube"
+)
+
+// Status is the outcome of a single check
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// Check is one line of the report
+type Check struct {
+	Name   string   `json:"name"`
+	Status Status   `json:"status"`
+	Detail string   `json:"detail,omitempty"`
+	Fixes  []string `json:"fixes,omitempty"`
+}
+
+// Report is the ordered result of all checks
+type Report struct {
+	Checks []Check `json:"checks"`
+}
+
+// Failed returns the number of failed checks
+func (r *Report) Failed() int {
+	n := 0
+	for _, c := range r.Checks {
+		if c.Status == StatusFail {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *Report) add(c Check) {
+	r.Checks = append(r.Checks, c)
+}
+
+// Options selects what the checks run against
+type Options struct {
+	Kubeconfig  string
+	KubeContext string
+	// Namespace is checked for RBAC; defaults to the context namespace
+	Namespace string
+	// Provider is the analysis configuration to check
+	Provider analysis.Config
+	// SkipQuery skips sending a test query to the provider
+	SkipQuery bool
+}
+
+// probeQuery is sent to check provider reachability and authentication
+const probeQuery = "Reply with the single word OK."
+
+// Run executes all checks. Checks that depend on a failed one are skipped.
+func Run(ctx context.Context, opts Options) *Report {
+	r := &Report{}
+	checkCluster(ctx, r, opts)
+	checkProvider(ctx, r, opts)
+	return r
+}
+
+func checkCluster(ctx context.Context, r *Report, opts Options) {
+	cluster, err := auth.LoadCluster(opts.Kubeconfig, opts.KubeContext)
+	if err != nil {
+		r.add(Check{Name: "kubeconfig", Status: StatusFail, Detail: err.Error(), Fixes: []string{
+			"log in to the cluster (`oc login`) or pass --kubeconfig and --context",
+		}})
+		for _, name := range []string{"cluster connectivity", "tekton CRDs", "RBAC"} {
+			r.add(Check{Name: name, Status: StatusSkip, Detail: "no usable kubeconfig"})
+		}
+		return
+	}
+	r.add(Check{Name: "kubeconfig", Status: StatusPass, Detail: fmt.Sprintf("context %s, server %s", cluster.Context, cluster.Server)})
+
+	client, err := newClusterClient(cluster, opts.Provider.Timeout)
+	if err != nil {
+		r.add(Check{Name: "cluster connectivity", Status: StatusFail, Detail: err.Error(), Fixes: []string{
+			"fix the client credentials or CA of the context in " + cluster.Path,
+		}})
+		return
+	}
+
+	version, err := client.serverVersion(ctx)
+	if err != nil {
+		r.add(Check{Name: "cluster connectivity", Status: StatusFail, Detail: err.Error(), Fixes: clusterFixes(err)})
+		r.add(Check{Name: "tekton CRDs", Status: StatusSkip, Detail: "cluster not reachable"})
+		r.add(Check{Name: "RBAC", Status: StatusSkip, Detail: "cluster not reachable"})
+		return
+	}
+	r.add(Check{Name: "cluster connectivity", Status: StatusPass, Detail: "Kubernetes " + version})
+
+	r.add(checkTekton(ctx, client))
+
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = cluster.Namespace
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+	r.add(checkRBAC(ctx, client, namespace))
+}
+
+func checkTekton(ctx context.Context, client *clusterClient) Check {
+	group, err := client.tektonGroup(ctx)
+	var apiErr *kube.StatusError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.Status == 404:
+		return Check{Name: "tekton CRDs", Status: StatusFail, Detail: "the tekton.dev API group is not served", Fixes: []string{
+			"install the OpenShift Pipelines operator (or Tekton Pipelines) on the cluster",
+		}}
+	case err != nil:
+		return Check{Name: "tekton CRDs", Status: StatusFail, Detail: err.Error(), Fixes: clusterFixes(err)}
+	}
+
+	detail := fmt.Sprintf("tekton.dev %v (preferred %s)", group.versions(), group.PreferredVersion.Version)
+	if !group.serves("v1") {
+		return Check{Name: "tekton CRDs", Status: StatusWarn, Detail: detail, Fixes: []string{
+			"upgrade Tekton Pipelines; the tekton.dev/v1 API is required for full diagnoses",
+		}}
+	}
+	return Check{Name: "tekton CRDs", Status: StatusPass, Detail: detail}
+}
+
+// requiredAccess lists what diagnosing a run needs in its namespace
+var requiredAccess = []resourceAccess{
+	{Group: "tekton.dev", Resource: "taskruns", Verb: "get"},
+	{Group: "tekton.dev", Resource: "pipelineruns", Verb: "get"},
+	{Group: "", Resource: "pods", Subresource: "log", Verb: "get"},
+	{Group: "", Resource: "events", Verb: "list"},
+}
+
+func checkRBAC(ctx context.Context, client *clusterClient, namespace string) Check {
+	var denied []string
+	for _, a := range requiredAccess {
+		allowed, err := client.canI(ctx, namespace, a)
+		if err != nil {
+			return Check{Name: "RBAC", Status: StatusFail, Detail: err.Error(), Fixes: clusterFixes(err)}
+		}
+		if !allowed {
+			denied = append(denied, a.String())
+		}
+	}
+	if len(denied) > 0 {
+		return Check{Name: "RBAC", Status: StatusFail, Detail: fmt.Sprintf("missing in namespace %s: %v", namespace, denied), Fixes: []string{
+			fmt.Sprintf("ask a cluster admin for the view role: `oc adm policy add-role-to-user view <user> -n %s`", namespace),
+		}}
+	}
+	return Check{Name: "RBAC", Status: StatusPass, Detail: "can read runs, pod logs and events in namespace " + namespace}
+}
+
+func checkProvider(ctx context.Context, r *Report, opts Options) {
+	cfg := opts.Provider
+	// A cached answer would hide an unreachable provider
+	cfg.CacheTTL = 0
+	provider := cfg.Provider
+	if provider == "" {
+		provider = analysis.ProviderLightspeed
+	}
+
+	if err := analysis.ValidateConfig(cfg); err != nil {
+		var fixes []string
+		for _, e := range unjoin(err) {
+			fixes = append(fixes, e.Error())
+		}
+		r.add(Check{Name: "provider configuration", Status: StatusFail, Detail: provider, Fixes: fixes})
+		r.add(Check{Name: "provider query", Status: StatusSkip, Detail: "invalid provider configuration"})
+		return
+	}
+	r.add(Check{Name: "provider configuration", Status: StatusPass, Detail: provider})
+
+	if opts.SkipQuery {
+		r.add(Check{Name: "provider query", Status: StatusSkip, Detail: "skipped on request"})
+		return
+	}
+	if provider == analysis.ProviderRules {
+		r.add(Check{Name: "provider query", Status: StatusSkip, Detail: "the rules provider runs offline"})
+		return
+	}
+	llm, err := analysis.New(cfg)
+	if err != nil {
+		r.add(Check{Name: "provider query", Status: StatusFail, Detail: err.Error()})
+		return
+	}
+	start := time.Now()
+	if _, err := analysis.Run(ctx, llm, probeQuery); err != nil {
+		c := Check{Name: "provider query", Status: StatusFail, Detail: err.Error()}
+		var selfErr *analysis.SelfDiagnosisError
+		if errors.As(err, &selfErr) {
+			c.Detail = selfErr.Err.Error()
+			c.Fixes = selfErr.Remediation
+		}
+		r.add(c)
+		return
+	}
+	r.add(Check{Name: "provider query", Status: StatusPass, Detail: fmt.Sprintf("%s answered in %dms", provider, time.Since(start).Milliseconds())})
+}
+
+// unjoin returns the errors combined by errors.Join, or err itself
+func unjoin(err error) []error {
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		return j.Unwrap()
+	}
+	return []error{err}
+}
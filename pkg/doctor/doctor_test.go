@@ -0,0 +1,100 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doctor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/analysis"
+)
+
+func TestRun(t *testing.T) {
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer kube-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/version":
+			_, _ = w.Write([]byte(`{"gitVersion":"v1.31.2"}`))
+		case "/apis/tekton.dev":
+			_, _ = w.Write([]byte(`{"versions":[{"version":"v1"},{"version":"v1beta1"}],"preferredVersion":{"version":"v1"}}`))
+		case "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews":
+			body, _ := io.ReadAll(r.Body)
+			if strings.Contains(string(body), `"events"`) {
+				_, _ = w.Write([]byte(`{"status":{"allowed":false}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":{"allowed":true}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(apiServer.Close)
+	lightspeed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"response":"ok"}`))
+	}))
+	t.Cleanup(lightspeed.Close)
+
+	kubeconfig := t.TempDir() + "/config"
+	if err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: c
+  cluster:
+    server: `+apiServer.URL+`
+contexts:
+- name: dev
+  context: {cluster: c, user: u, namespace: ci}
+users:
+- name: u
+  user: {token: kube-token}
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	report := Run(context.Background(), Options{
+		Kubeconfig: kubeconfig,
+		Provider:   analysis.Config{BaseURL: lightspeed.URL, Token: "kube-token"},
+	})
+
+	got := map[string]Status{}
+	for _, c := range report.Checks {
+		got[c.Name] = c.Status
+	}
+	want := map[string]Status{
+		"kubeconfig":             StatusPass,
+		"cluster connectivity":   StatusPass,
+		"tekton CRDs":            StatusPass,
+		"RBAC":                   StatusFail,
+		"provider configuration": StatusPass,
+		"provider query":         StatusPass,
+	}
+	for name, status := range want {
+		if got[name] != status {
+			t.Errorf("check %q: got %q, want %q (report %+v)", name, got[name], status, report.Checks)
+		}
+	}
+	if report.Failed() != 1 {
+		t.Fatalf("expected one failed check, got %d", report.Failed())
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+// catalogs maps a language tag to translations keyed by the English text
+var catalogs = map[string]map[string]string{
+	"ja": {
+		"TaskRun Diagnosis Report":                "TaskRun 診断レポート",
+		"PipelineRun Diagnosis Report":            "PipelineRun 診断レポート",
+		"Log Explanation":                         "ログの解説",
+		"API Response:":                           "API レスポンス:",
+		"Response:":                               "レスポンス:",
+		"Summary:":                                "概要:",
+		"Analysis & Suggested Remediation:":       "分析と推奨される対処:",
+		"Analysis & Recommendations:":             "分析と推奨事項:",
+		"Solutions:":                              "解決策:",
+		"References:":                             "参考資料:",
+		"Conditions:":                             "コンディション:",
+		"Token usage: input %.0f, output %.0f":    "トークン使用量: 入力 %.0f、出力 %.0f",
+		"Analyzed by: %s, prompt %s/%s in %.0fms": "分析: %s、プロンプト %s/%s、%.0fms",
+		"Estimated cost: $%.4f":                   "推定コスト: $%.4f",
+		"Prompt:":                                 "プロンプト:",
+		"%d of %d log lines included, %d estimated tokens (budget %d)": "ログ %[2]d 行中 %[1]d 行を使用、推定 %[3]d トークン (上限 %[4]d)",
+		"audience %s, hint %t, %d estimated prompt tokens":             "対象読者 %s、ヒント %t、プロンプト推定 %d トークン",
+		"Namespace":                        "Namespace",
+		"Status":                           "ステータス",
+		"Start Time":                       "開始時刻",
+		"Completion Time":                  "完了時刻",
+		"Duration: %.0f seconds":           "所要時間: %.0f 秒",
+		"Failed TaskRuns (%d):":            "失敗した TaskRun (%d):",
+		"Failed TaskRuns: None":            "失敗した TaskRun: なし",
+		"Reason":                           "理由",
+		"Message":                          "メッセージ",
+		"TaskRun":                          "TaskRun",
+		"PipelineRun":                      "PipelineRun",
+		"UID":                              "UID",
+		"Succeeded":                        "成功",
+		"Yes":                              "はい",
+		"No":                               "いいえ",
+		"Failed Step":                      "失敗したステップ",
+		"Exit Code":                        "終了コード",
+		"Error Details:":                   "エラーの詳細:",
+		"Type":                             "種類",
+		"Log Snippet:":                     "ログの抜粋:",
+		"Analyzed at":                      "分析日時",
+		"Failed Steps:":                    "失敗したステップ:",
+		"Error Messages:":                  "エラーメッセージ:",
+		"Analysis:":                        "分析:",
+		"Recommendations:":                 "推奨事項:",
+		"Confidence: %s (%.2f, %s)":        "信頼度: %s (%.2f、%s)",
+		"low":                              "低",
+		"medium":                           "中",
+		"high":                             "高",
+		"model":                            "モデル",
+		"heuristic":                        "推定",
+		"Failure Review":                   "失敗のレビュー",
+		"%d runs, %d diagnosed, %d failed": "%d 件の実行、%d 件を診断、%d 件が失敗",
+		"Run":                              "実行",
+		"Category":                         "カテゴリ",
+		"Confidence":                       "信頼度",
+		"Root cause":                       "根本原因",
+		"Group:":                           "グループ:",
+		"YAML snippet %d is not valid: %s": "YAML スニペット %d は無効です: %s",
+		"pass":                             "成功",
+		"warn":                             "警告",
+		"fail":                             "失敗",
+		"skip":                             "スキップ",
+		"fix":                              "対処",
+		"error":                            "エラー",
+	},
+	"pt-BR": {
+		"TaskRun Diagnosis Report":                "Relatório de diagnóstico do TaskRun",
+		"PipelineRun Diagnosis Report":            "Relatório de diagnóstico do PipelineRun",
+		"Log Explanation":                         "Explicação do log",
+		"API Response:":                           "Resposta da API:",
+		"Response:":                               "Resposta:",
+		"Summary:":                                "Resumo:",
+		"Analysis & Suggested Remediation:":       "Análise e correção sugerida:",
+		"Analysis & Recommendations:":             "Análise e recomendações:",
+		"Solutions:":                              "Soluções:",
+		"References:":                             "Referências:",
+		"Conditions:":                             "Condições:",
+		"Token usage: input %.0f, output %.0f":    "Uso de tokens: entrada %.0f, saída %.0f",
+		"Analyzed by: %s, prompt %s/%s in %.0fms": "Analisado por: %s, prompt %s/%s em %.0fms",
+		"Estimated cost: $%.4f":                   "Custo estimado: US$ %.4f",
+		"Prompt:":                                 "Prompt:",
+		"%d of %d log lines included, %d estimated tokens (budget %d)": "%d de %d linhas do log incluídas, %d tokens estimados (limite %d)",
+		"audience %s, hint %t, %d estimated prompt tokens":             "público %s, dica %t, %d tokens estimados no prompt",
+		"Namespace":                        "Namespace",
+		"Status":                           "Status",
+		"Start Time":                       "Início",
+		"Completion Time":                  "Conclusão",
+		"Duration: %.0f seconds":           "Duração: %.0f segundos",
+		"Failed TaskRuns (%d):":            "TaskRuns com falha (%d):",
+		"Failed TaskRuns: None":            "TaskRuns com falha: nenhum",
+		"Reason":                           "Motivo",
+		"Message":                          "Mensagem",
+		"TaskRun":                          "TaskRun",
+		"PipelineRun":                      "PipelineRun",
+		"UID":                              "UID",
+		"Succeeded":                        "Concluído com sucesso",
+		"Yes":                              "Sim",
+		"No":                               "Não",
+		"Failed Step":                      "Etapa com falha",
+		"Exit Code":                        "Código de saída",
+		"Error Details:":                   "Detalhes do erro:",
+		"Type":                             "Tipo",
+		"Log Snippet:":                     "Trecho do log:",
+		"Analyzed at":                      "Analisado em",
+		"Failed Steps:":                    "Etapas com falha:",
+		"Error Messages:":                  "Mensagens de erro:",
+		"Analysis:":                        "Análise:",
+		"Recommendations:":                 "Recomendações:",
+		"Confidence: %s (%.2f, %s)":        "Confiança: %s (%.2f, %s)",
+		"low":                              "baixa",
+		"medium":                           "média",
+		"high":                             "alta",
+		"model":                            "modelo",
+		"heuristic":                        "estimada",
+		"Failure Review":                   "Revisão de falhas",
+		"%d runs, %d diagnosed, %d failed": "%d execuções, %d diagnosticadas, %d com falha",
+		"Run":                              "Execução",
+		"Category":                         "Categoria",
+		"Confidence":                       "Confiança",
+		"Root cause":                       "Causa raiz",
+		"Group:":                           "Grupo:",
+		"YAML snippet %d is not valid: %s": "O trecho YAML %d não é válido: %s",
+		"pass":                             "ok",
+		"warn":                             "aviso",
+		"fail":                             "falha",
+		"skip":                             "ignorado",
+		"fix":                              "correção",
+		"error":                            "erro",
+	},
+}
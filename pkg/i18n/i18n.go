@@ -0,0 +1,179 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package i18n translates the fixed text of reports and CLI output. Text
+// written by the analysis provider is not translated here.
+//
+// Messages are looked up by their English text, so untranslated messages
+// fall back to English.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// LanguageEnv overrides the language taken from the locale
+const LanguageEnv = "TKN_ASSIST_LANG"
+
+var (
+	mu        sync.RWMutex
+	requested string
+	current   string
+	loaded    bool
+)
+
+// SetLanguage selects the catalog by BCP 47 or POSIX locale tag (e.g.
+// "ja", "pt-BR", "pt_BR.UTF-8"). Unknown languages fall back to English.
+func SetLanguage(tag string) {
+	mu.Lock()
+	defer mu.Unlock()
+	requested = Normalize(tag)
+	current = match(requested)
+	loaded = true
+}
+
+// Language returns the selected catalog, detecting it from the
+// environment on first use
+func Language() string {
+	current, _ := state()
+	return current
+}
+
+// Requested returns the normalized tag that was asked for, which may have
+// no catalog (e.g. "de-AT" while reports fall back to English)
+func Requested() string {
+	_, requested := state()
+	return requested
+}
+
+func state() (string, string) {
+	mu.RLock()
+	if loaded {
+		defer mu.RUnlock()
+		return current, requested
+	}
+	mu.RUnlock()
+	SetLanguage(Detect())
+	return state()
+}
+
+// Detect returns the language requested by TKN_ASSIST_LANG or the POSIX
+// locale variables, in order of precedence
+func Detect() string {
+	for _, env := range []string{LanguageEnv, "LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(env)
+		if tag := Normalize(v); v != "" && tag != "c" && tag != "posix" {
+			return v
+		}
+	}
+	return "en"
+}
+
+// Normalize turns a BCP 47 or POSIX locale tag into "lang" or
+// "lang-REGION", dropping any encoding or modifier
+func Normalize(tag string) string {
+	tag, _, _ = strings.Cut(tag, ".")
+	tag, _, _ = strings.Cut(tag, "@")
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	lang, region, _ := strings.Cut(tag, "-")
+	lang = strings.ToLower(lang)
+	if lang == "" {
+		return "en"
+	}
+	if region == "" {
+		return lang
+	}
+	return lang + "-" + strings.ToUpper(region)
+}
+
+// match returns the closest catalog for a normalized tag: the full
+// language-region tag, then the language alone, then English
+func match(tag string) string {
+	if _, ok := catalogs[tag]; ok {
+		return tag
+	}
+	lang, _, _ := strings.Cut(tag, "-")
+	for t := range catalogs {
+		if strings.HasPrefix(t, lang+"-") || t == lang {
+			return t
+		}
+	}
+	return "en"
+}
+
+// languageNames are the English names of common languages, used to
+// instruct the model
+var languageNames = map[string]string{
+	"ja": "Japanese", "pt": "Portuguese", "pt-BR": "Brazilian Portuguese", "pt-PT": "European Portuguese",
+	"es": "Spanish", "fr": "French", "de": "German", "it": "Italian", "nl": "Dutch", "ko": "Korean",
+	"zh": "Chinese", "zh-CN": "Simplified Chinese", "zh-TW": "Traditional Chinese", "ru": "Russian",
+	"pl": "Polish", "tr": "Turkish", "hi": "Hindi", "sv": "Swedish", "cs": "Czech", "uk": "Ukrainian",
+}
+
+// Name returns the English name of the language of tag for use in a
+// prompt, or "" for English. Languages without a known name are described
+// by their tag.
+func Name(tag string) string {
+	tag = Normalize(tag)
+	lang, _, _ := strings.Cut(tag, "-")
+	if lang == "en" {
+		return ""
+	}
+	if name, ok := languageNames[tag]; ok {
+		return name
+	}
+	if name, ok := languageNames[lang]; ok {
+		return name
+	}
+	return "the language with BCP 47 tag " + tag
+}
+
+// T returns the translation of msg
+func T(msg string) string {
+	if s, ok := catalogs[Language()][msg]; ok {
+		return s
+	}
+	return msg
+}
+
+// Sprintf translates format and formats it with args
+func Sprintf(format string, args ...interface{}) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Heading returns the translated title underlined to its display width
+func Heading(title string) string {
+	title = T(title)
+	return title + "\n" + strings.Repeat("=", width(title))
+}
+
+// width approximates the terminal width of s, counting East Asian wide
+// characters as two columns
+func width(s string) int {
+	w := 0
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Han, r), unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r),
+			r >= 0xFF01 && r <= 0xFF60, r >= 0x3000 && r <= 0x303F:
+			w += 2
+		default:
+			w++
+		}
+	}
+	return w
+}
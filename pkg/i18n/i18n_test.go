@@ -0,0 +1,36 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+import (
+	"testing"
+)
+
+func TestCatalogs(t *testing.T) {
+	t.Cleanup(func() { SetLanguage("en") })
+
+	SetLanguage("pt_BR.UTF-8")
+	if got := T("Summary:"); got != "Resumo:" {
+		t.Fatalf("expected the pt-BR catalog, got %q", got)
+	}
+	SetLanguage("ja_JP.UTF-8")
+	if got := Heading("Log Explanation"); got != "ログの解説\n==========" {
+		t.Fatalf("expected a heading underlined to its display width, got %q", got)
+	}
+	SetLanguage("fr")
+	if got := Sprintf("Duration: %.0f seconds", 3.0); got != "Duration: 3 seconds" {
+		t.Fatalf("expected the English fallback, got %q", got)
+	}
+}
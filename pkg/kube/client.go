@@ -0,0 +1,131 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kube makes the few raw API server calls the CLI needs without
+// depending on client-go
+package kube
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/tekton-assist/pkg/auth"
+)
+
+// Client calls the API server of a kubeconfig cluster
+type Client struct {
+	server string
+	token  string
+	client *http.Client
+}
+
+// StatusError is a non-2xx API server response
+type StatusError struct {
+	Status int
+	Path   string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned HTTP %d", e.Path, e.Status)
+}
+
+// NewClient returns a Client for c. A zero timeout selects 30 seconds.
+func NewClient(c *auth.Cluster, timeout time.Duration) (*Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.InsecureSkipTLSVerify} //nolint:gosec // mirrors the kubeconfig setting
+	if len(c.CAData) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(c.CAData) {
+			return nil, fmt.Errorf("no PEM certificates in the cluster CA of context %s", c.Context)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if len(c.ClientCertData) > 0 {
+		cert, err := tls.X509KeyPair(c.ClientCertData, c.ClientKeyData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate of context %s: %w", c.Context, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &Client{
+		server: strings.TrimSuffix(c.Server, "/"),
+		token:  c.Token,
+		client: &http.Client{Timeout: timeout, Transport: transport},
+	}, nil
+}
+
+// Timeout returns the request timeout of the client
+func (c *Client) Timeout() time.Duration {
+	return c.client.Timeout
+}
+
+// Do sends body (when non-nil) as JSON to path and decodes the response
+// into out
+func (c *Client) Do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.server+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &StatusError{Status: resp.StatusCode, Path: path}
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// ConfigMap returns the data of a ConfigMap
+func (c *Client) ConfigMap(ctx context.Context, namespace, name string) (map[string]string, error) {
+	var cm struct {
+		Data map[string]string `json:"data"`
+	}
+	path := "/api/v1/namespaces/" + url.PathEscape(namespace) + "/configmaps/" + url.PathEscape(name)
+	if err := c.Do(ctx, http.MethodGet, path, nil, &cm); err != nil {
+		return nil, err
+	}
+	return cm.Data, nil
+}
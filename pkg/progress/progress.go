@@ -0,0 +1,106 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package progress emits machine-readable progress events so IDE extensions
+// and wrappers can follow a multi-step diagnosis.
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Stage names reported by the CLI
+const (
+	StageReadingLog          = "reading_log"
+	StageResolvingCredential = "resolving_credentials"
+	StageCallingLLM          = "calling_llm"
+	StageRendering           = "rendering"
+)
+
+// Status values of an Event
+const (
+	StatusStarted   = "started"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// Event is a single progress update
+type Event struct {
+	Time      time.Time `json:"time"`
+	Stage     string    `json:"stage"`
+	Status    string    `json:"status"`
+	ElapsedMS int64     `json:"elapsed_ms,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Reporter receives progress updates
+type Reporter interface {
+	// Start reports that a stage began and returns a function that
+	// reports its completion (or failure when err is non-nil)
+	Start(stage string) func(err error)
+}
+
+// New returns the Reporter for the given --progress format ("" or "none"
+// disables reporting; "ndjson" writes one JSON event per line to w)
+func New(format string, w io.Writer) (Reporter, error) {
+	switch format {
+	case "", "none":
+		return Nop{}, nil
+	case "ndjson":
+		return &ndjsonReporter{enc: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("unknown progress format %q (expected none or ndjson)", format)
+	}
+}
+
+// Nop is a Reporter that discards all events
+type Nop struct{}
+
+// Start implements Reporter
+func (Nop) Start(string) func(error) {
+	return func(error) {}
+}
+
+type ndjsonReporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (r *ndjsonReporter) Start(stage string) func(error) {
+	start := time.Now()
+	r.emit(Event{Time: start, Stage: stage, Status: StatusStarted})
+	return func(err error) {
+		ev := Event{
+			Time:      time.Now(),
+			Stage:     stage,
+			Status:    StatusCompleted,
+			ElapsedMS: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			ev.Status = StatusFailed
+			ev.Error = err.Error()
+		}
+		r.emit(ev)
+	}
+}
+
+func (r *ndjsonReporter) emit(ev Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_ = r.enc.Encode(ev)
+}
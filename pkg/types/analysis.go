@@ -0,0 +1,86 @@
+// Copyright 2025 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types holds the structured analysis returned to automation, so
+// consumers do not have to parse the model's prose.
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Category classifies the root cause of a failure
+type Category string
+
+const (
+	CategoryImage          Category = "image"
+	CategoryResources      Category = "resources"
+	CategoryTimeout        Category = "timeout"
+	CategoryWorkspace      Category = "workspace"
+	CategoryParams         Category = "params"
+	CategoryAuth           Category = "auth"
+	CategoryConfig         Category = "config"
+	CategoryScript         Category = "script"
+	CategoryInfrastructure Category = "infrastructure"
+	CategoryUnknown        Category = "unknown"
+)
+
+// Categories lists the valid categories
+var Categories = []Category{
+	CategoryImage, CategoryResources, CategoryTimeout, CategoryWorkspace, CategoryParams,
+	CategoryAuth, CategoryConfig, CategoryScript, CategoryInfrastructure, CategoryUnknown,
+}
+
+// Analysis is the structured diagnosis of a failure
+type Analysis struct {
+	RootCause  string   `json:"root_cause"`
+	Category   Category `json:"category"`
+	Solutions  []string `json:"solutions"`
+	Confidence float64  `json:"confidence"`
+}
+
+// Validate checks a parsed Analysis against the schema requested from the
+// model
+func (a *Analysis) Validate() error {
+	var errs []error
+	if strings.TrimSpace(a.RootCause) == "" {
+		errs = append(errs, errors.New("root_cause is empty"))
+	}
+	if !a.Category.valid() {
+		errs = append(errs, fmt.Errorf("unknown category %q", a.Category))
+	}
+	if len(a.Solutions) == 0 {
+		errs = append(errs, errors.New("solutions is empty"))
+	}
+	for i, s := range a.Solutions {
+		if strings.TrimSpace(s) == "" {
+			errs = append(errs, fmt.Errorf("solution %d is empty", i+1))
+		}
+	}
+	if a.Confidence < 0 || a.Confidence > 1 {
+		errs = append(errs, fmt.Errorf("confidence %v is outside [0, 1]", a.Confidence))
+	}
+	return errors.Join(errs...)
+}
+
+func (c Category) valid() bool {
+	for _, v := range Categories {
+		if c == v {
+			return true
+		}
+	}
+	return false
+}
@@ -18,14 +18,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/openshift-pipelines/tekton-assist/pkg/analysis"
+	"github.com/openshift-pipelines/tekton-assist/pkg/assist"
 	cli "github.com/openshift-pipelines/tekton-assist/pkg/cli"
 )
 
@@ -140,4 +144,311 @@ func TestE2E_TaskRun_JSONOutput(t *testing.T) {
 	if _, ok := js["response"]; !ok {
 		t.Fatalf("missing 'response' field in JSON: %s", buf.String())
 	}
+	meta, ok := js["metadata"].(map[string]any)
+	if !ok {
+		t.Fatalf("missing 'metadata' field in JSON: %s", buf.String())
+	}
+	if meta["provider"] != "lightspeed" || meta["prompt_version"] == "" {
+		t.Fatalf("unexpected metadata: %v", meta)
+	}
+}
+
+func TestE2E_TaskRun_ProblemJSONError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"type":"about:blank","title":"Unauthorized","detail":"token expired"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	root := cli.RootCommand()
+	root.SetArgs([]string{
+		"taskrun", "diagnose", "demo", "-n", "default",
+		"--lightspeed-url", srv.URL,
+	})
+	err := root.ExecuteContext(ctx)
+
+	var apiErr *analysis.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *analysis.APIError, got %T: %v", err, err)
+	}
+	if apiErr.Code != analysis.CodeUnauthorized {
+		t.Fatalf("expected code %s, got %s", analysis.CodeUnauthorized, apiErr.Code)
+	}
+	if apiErr.Detail != "token expired" {
+		t.Fatalf("unexpected detail: %q", apiErr.Detail)
+	}
+	var selfDiag *analysis.SelfDiagnosisError
+	if !errors.As(err, &selfDiag) || len(selfDiag.Remediation) == 0 {
+		t.Fatalf("expected remediation steps, got: %v", err)
+	}
+}
+
+func TestE2E_Explain_File(t *testing.T) {
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		gotQuery, _ = payload["query"].(string)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"response":  "The build failed because npm could not be found.",
+			"solutions": []string{"Use an image that ships npm."},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	logFile := t.TempDir() + "/build.log"
+	if err := os.WriteFile(logFile, []byte("step 1\n/bin/sh: npm: not found\nexit status 127\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	root := cli.RootCommand()
+	root.SetArgs([]string{"explain", "-f", logFile, "--lightspeed-url", srv.URL})
+
+	oldStdout := os.Stdout
+	rOut, wOut, _ := os.Pipe()
+	os.Stdout = wOut
+	err := root.ExecuteContext(ctx)
+	_ = wOut.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, rOut)
+	if err != nil {
+		t.Fatalf("command failed: %v\n%s", err, buf.String())
+	}
+
+	if !strings.Contains(gotQuery, "npm: not found") {
+		t.Fatalf("log snippet not sent in query: %q", gotQuery)
+	}
+	got := buf.String()
+	if !strings.Contains(got, "Log Explanation") || !strings.Contains(got, "Use an image that ships npm.") {
+		t.Fatalf("unexpected output:\n%s", got)
+	}
+}
+
+func TestE2E_Chat_ReplaysHistory(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		q, _ := payload["query"].(string)
+		queries = append(queries, q)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"response": "answer " + string(rune('0'+len(queries)))})
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	root := cli.RootCommand()
+	root.SetArgs([]string{"chat", "--run", "demo", "-n", "default", "--lightspeed-url", srv.URL})
+	root.SetIn(strings.NewReader("which step failed?\nexit\n"))
+	var out bytes.Buffer
+	root.SetOut(&out)
+	if err := root.ExecuteContext(ctx); err != nil {
+		t.Fatalf("command failed: %v\n%s", err, out.String())
+	}
+
+	if len(queries) != 2 {
+		t.Fatalf("expected 2 queries, got %d", len(queries))
+	}
+	if !strings.Contains(queries[1], "answer 1") || !strings.Contains(queries[1], "which step failed?") {
+		t.Fatalf("follow-up did not replay history: %q", queries[1])
+	}
+	if !strings.Contains(out.String(), "answer 2") {
+		t.Fatalf("missing follow-up answer:\n%s", out.String())
+	}
+}
+
+func TestE2E_Explain_RulesFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+	logFile := filepath.Join(t.TempDir(), "build.log")
+	if err := os.WriteFile(logFile, []byte("step 1\n/bin/sh: npm: not found\nexit status 127\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	root := cli.RootCommand()
+	root.SetArgs([]string{"explain", "-f", logFile, "--lightspeed-url", srv.URL, "-o", "json"})
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	rOut, wOut, _ := os.Pipe()
+	os.Stdout, os.Stderr = wOut, wOut
+	err := root.ExecuteContext(context.Background())
+	_ = wOut.Close()
+	os.Stdout, os.Stderr = oldStdout, oldStderr
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, rOut)
+	if err != nil {
+		t.Fatalf("expected the rules to answer when the provider fails: %v\n%s", err, buf.String())
+	}
+	if got := buf.String(); !strings.Contains(got, "WARNING: lightspeed failed") || !strings.Contains(got, `"category": "script"`) {
+		t.Fatalf("unexpected output:\n%s", got)
+	}
+}
+
+func TestE2E_Explain_RulesProvider(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "build.log")
+	if err := os.WriteFile(logFile, []byte("step 1\n/bin/sh: npm: not found\nexit status 127\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(t, "explain", "-f", logFile, "--provider", "rules", "-o", "json")
+	if err != nil {
+		t.Fatalf("explain failed: %v\n%s", err, out)
+	}
+	var got struct {
+		Confidence float64             `json:"confidence"`
+		Assessment analysis.Confidence `json:"confidence_assessment"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if got.Confidence != 0.7 || got.Assessment.Score != 0.7 || got.Assessment.Level != analysis.ConfidenceHigh || got.Assessment.Source != analysis.ConfidenceFromModel {
+		t.Fatalf("unexpected confidence: %+v\n%s", got, out)
+	}
+
+	out, err = runCLI(t, "explain", "-f", logFile, "--provider", "rules")
+	if err != nil || !strings.Contains(out, "Confidence: high (0.70, model)") {
+		t.Fatalf("unexpected text output: %v\n%s", err, out)
+	}
+}
+
+func TestE2E_DiagnoseBatch(t *testing.T) {
+	srv := mockLightspeedServer(t)
+	t.Cleanup(srv.Close)
+
+	runs := filepath.Join(t.TempDir(), "runs.txt")
+	content := "# weekly review\nteam-a/build-1\n\ntaskrun/team-b/unit-2\n{\"kind\":\"pr\",\"name\":\"release-3\"}\n"
+	if err := os.WriteFile(runs, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	root := cli.RootCommand()
+	root.SetArgs([]string{"diagnose", "--batch", runs, "-n", "team-c", "--concurrency", "2", "-o", "json", "--lightspeed-url", srv.URL})
+	oldStdout := os.Stdout
+	rOut, wOut, _ := os.Pipe()
+	os.Stdout = wOut
+	err := root.ExecuteContext(context.Background())
+	_ = wOut.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, rOut)
+	if err != nil {
+		t.Fatalf("command failed: %v\n%s", err, buf.String())
+	}
+
+	var report struct {
+		Total     int `json:"total"`
+		Diagnosed int `json:"diagnosed"`
+		Runs      []struct {
+			Kind      string `json:"kind"`
+			Namespace string `json:"namespace"`
+			Name      string `json:"name"`
+			Summary   string `json:"summary"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON report: %v\n%s", err, buf.String())
+	}
+	if report.Total != 3 || report.Diagnosed != 3 {
+		t.Fatalf("expected 3 diagnosed runs, got %+v", report)
+	}
+	got := []string{}
+	for _, r := range report.Runs {
+		got = append(got, r.Kind+" "+r.Namespace+"/"+r.Name)
+	}
+	if want := "PipelineRun team-a/build-1,TaskRun team-b/unit-2,PipelineRun team-c/release-3"; strings.Join(got, ",") != want {
+		t.Fatalf("expected runs in file order %s, got %v", want, got)
+	}
+
+	if _, err := assist.ParseRefs([]byte("a/b/c/d"), assist.KindTaskRun, ""); err == nil {
+		t.Error("expected a malformed reference to be rejected")
+	}
+	refs, err := assist.ParseRefs([]byte(`[{"kind":"taskrun","namespace":"ns","name":"x"}]`), assist.KindPipelineRun, "")
+	if err != nil || len(refs) != 1 || refs[0].Kind != assist.KindTaskRun {
+		t.Errorf("unexpected refs from a JSON array: %+v, %v", refs, err)
+	}
+}
+
+func TestE2E_SystemPrompt(t *testing.T) {
+	var systemPrompt string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			SystemPrompt string `json:"system_prompt"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		systemPrompt = body.SystemPrompt
+		_, _ = w.Write([]byte(`{"response":"ok"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	kubeconfig := filepath.Join(dir, "config")
+	if err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+current-context: prod
+clusters:
+- name: c
+  cluster: {server: "https://api.prod.example.com:6443"}
+contexts:
+- name: prod
+  context: {cluster: c, user: u}
+users:
+- name: u
+  user: {token: kube-token}
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	logFile := filepath.Join(dir, "build.log")
+	if err := os.WriteFile(logFile, []byte("error: exit status 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	root := cli.RootCommand()
+	root.SetArgs([]string{"explain", "-f", logFile, "--lightspeed-url", srv.URL, "--kubeconfig", kubeconfig, "-o", "json",
+		"--system-prompt", "You debug Tekton runs on {{.Cluster}} ({{.Context}}). {{.Vars.conventions}}",
+		"--prompt-var", "conventions=Pipelines are owned by the team in the app.kubernetes.io/part-of label."})
+	oldStdout := os.Stdout
+	_, wOut, _ := os.Pipe()
+	os.Stdout = wOut
+	err := root.ExecuteContext(context.Background())
+	_ = wOut.Close()
+	os.Stdout = oldStdout
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "You debug Tekton runs on api.prod.example.com (prod). Pipelines are owned by the team in the app.kubernetes.io/part-of label."; systemPrompt != want {
+		t.Fatalf("unexpected system prompt %q", systemPrompt)
+	}
+}
+
+// runCLI runs tkn-assist with args and returns what it wrote to stdout and
+// stderr
+func runCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	root := cli.RootCommand()
+	root.SetArgs(args)
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	rOut, wOut, _ := os.Pipe()
+	os.Stdout, os.Stderr = wOut, wOut
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, rOut)
+		done <- buf.String()
+	}()
+	err := root.ExecuteContext(context.Background())
+	_ = wOut.Close()
+	os.Stdout, os.Stderr = oldStdout, oldStderr
+	return <-done, err
 }